@@ -0,0 +1,318 @@
+package auth
+
+import (
+	"strconv"
+	"strings"
+)
+
+type Algorithm string
+
+// Digest algorithms as registered in RFC 7616 and RFC 8760
+const (
+	// MD5 is assumed when no algorithm is specified
+	MD5           Algorithm = "MD5"
+	MD5Sess       Algorithm = "MD5-sess"
+	SHA256        Algorithm = "SHA-256"
+	SHA256Sess    Algorithm = "SHA-256-sess"
+	SHA512256     Algorithm = "SHA-512-256"
+	SHA512256Sess Algorithm = "SHA-512-256-sess"
+)
+
+func parseAlgorithm(value string) (Algorithm, error) {
+	for _, algorithm := range [...]Algorithm{
+		MD5, MD5Sess, SHA256, SHA256Sess, SHA512256, SHA512256Sess,
+	} {
+		if strings.EqualFold(value, string(algorithm)) {
+			return algorithm, nil
+		}
+	}
+
+	return "", ErrUnknownAlgorithm
+}
+
+// Session reports whether the algorithm is a session variant, in which case the
+// client nonce is mixed into the first hash
+func (a Algorithm) Session() bool {
+	return strings.HasSuffix(string(a), "-sess")
+}
+
+type QOP string
+
+const (
+	Auth    QOP = "auth"
+	AuthInt QOP = "auth-int"
+)
+
+func parseQOP(value string) (QOP, error) {
+	switch {
+	case strings.EqualFold(value, string(Auth)):
+		return Auth, nil
+	case strings.EqualFold(value, string(AuthInt)):
+		return AuthInt, nil
+	default:
+		return "", ErrUnknownQOP
+	}
+}
+
+// Challenge is the content of the WWW-Authenticate and Proxy-Authenticate headers
+type Challenge struct {
+	Realm  string // compulsory
+	Domain string // optional
+	Nonce  string // compulsory
+	Opaque string // optional
+	Stale  bool   // optional
+	// Algorithm is empty when the parameter is omitted, that must be treated as MD5
+	Algorithm Algorithm // optional
+	QOP       []QOP     // optional
+}
+
+func (c Challenge) Parse(value string) (Challenge, error) {
+	value, err := trimScheme(value)
+	if err != nil {
+		return c, err
+	}
+
+	err = parseParams(value, func(key, value string) (err error) {
+		switch strings.ToLower(key) {
+		case "realm":
+			c.Realm = value
+		case "domain":
+			c.Domain = value
+		case "nonce":
+			c.Nonce = value
+		case "opaque":
+			c.Opaque = value
+		case "stale":
+			c.Stale = strings.EqualFold(value, "true")
+		case "algorithm":
+			c.Algorithm, err = parseAlgorithm(value)
+		case "qop":
+			for _, option := range strings.Split(value, ",") {
+				// unknown options must be ignored, as the client is free to choose
+				// any of the offered
+				if qop, err := parseQOP(strings.TrimSpace(option)); err == nil {
+					c.QOP = append(c.QOP, qop)
+				}
+			}
+		}
+
+		return err
+	})
+	if err != nil {
+		return c, err
+	}
+
+	if len(c.Realm) == 0 || len(c.Nonce) == 0 {
+		return c, ErrMissingParam
+	}
+
+	return c, nil
+}
+
+func (c Challenge) String() string {
+	buff := make([]byte, 0, 128)
+	buff = append(buff, digestScheme+" "...)
+	buff = appendParam(buff, "realm", c.Realm, true)
+
+	if len(c.Domain) > 0 {
+		buff = appendParam(buff, "domain", c.Domain, true)
+	}
+
+	buff = appendParam(buff, "nonce", c.Nonce, true)
+
+	if len(c.Opaque) > 0 {
+		buff = appendParam(buff, "opaque", c.Opaque, true)
+	}
+
+	if c.Stale {
+		buff = appendParam(buff, "stale", "true", false)
+	}
+
+	if len(c.Algorithm) > 0 {
+		buff = appendParam(buff, "algorithm", string(c.Algorithm), false)
+	}
+
+	if len(c.QOP) > 0 {
+		options := make([]string, len(c.QOP))
+		for i := range c.QOP {
+			options[i] = string(c.QOP[i])
+		}
+
+		buff = appendParam(buff, "qop", strings.Join(options, ","), true)
+	}
+
+	return string(buff)
+}
+
+// Credentials is the content of the Authorization and Proxy-Authorization headers
+type Credentials struct {
+	Username string // compulsory
+	Realm    string // compulsory
+	Nonce    string // compulsory
+	URI      string // compulsory
+	// Response is 32 hex digits for MD5 and 64 hex digits for SHA-256 and SHA-512-256
+	Response  string    // compulsory
+	Algorithm Algorithm // optional
+	CNonce    string    // compulsory if QOP is presented
+	Opaque    string    // optional
+	QOP       QOP       // optional
+	NC        uint32    // compulsory if QOP is presented
+}
+
+func (c Credentials) Parse(value string) (Credentials, error) {
+	value, err := trimScheme(value)
+	if err != nil {
+		return c, err
+	}
+
+	// nc has no value to tell it's omitted, as any is valid
+	var ncFound bool
+
+	err = parseParams(value, func(key, value string) (err error) {
+		switch strings.ToLower(key) {
+		case "username":
+			c.Username = value
+		case "realm":
+			c.Realm = value
+		case "nonce":
+			c.Nonce = value
+		case "uri":
+			c.URI = value
+		case "response":
+			c.Response = value
+		case "algorithm":
+			c.Algorithm, err = parseAlgorithm(value)
+		case "cnonce":
+			c.CNonce = value
+		case "opaque":
+			c.Opaque = value
+		case "qop":
+			c.QOP, err = parseQOP(value)
+		case "nc":
+			c.NC, err = parseNonceCount(value)
+			ncFound = true
+		}
+
+		return err
+	})
+	if err != nil {
+		return c, err
+	}
+
+	if len(c.Username) == 0 || len(c.Realm) == 0 || len(c.Nonce) == 0 || len(c.URI) == 0 || len(c.Response) == 0 {
+		return c, ErrMissingParam
+	}
+
+	if len(c.QOP) > 0 && (len(c.CNonce) == 0 || !ncFound) {
+		return c, ErrMissingParam
+	}
+
+	return c, nil
+}
+
+func (c Credentials) String() string {
+	buff := make([]byte, 0, 256)
+	buff = append(buff, digestScheme+" "...)
+	buff = appendParam(buff, "username", c.Username, true)
+	buff = appendParam(buff, "realm", c.Realm, true)
+	buff = appendParam(buff, "nonce", c.Nonce, true)
+	buff = appendParam(buff, "uri", c.URI, true)
+	buff = appendParam(buff, "response", c.Response, true)
+
+	if len(c.Algorithm) > 0 {
+		buff = appendParam(buff, "algorithm", string(c.Algorithm), false)
+	}
+
+	if len(c.CNonce) > 0 {
+		buff = appendParam(buff, "cnonce", c.CNonce, true)
+	}
+
+	if len(c.Opaque) > 0 {
+		buff = appendParam(buff, "opaque", c.Opaque, true)
+	}
+
+	if len(c.QOP) > 0 {
+		buff = appendParam(buff, "qop", string(c.QOP), false)
+		buff = appendParam(buff, "nc", formatNonceCount(c.NC), false)
+	}
+
+	return string(buff)
+}
+
+// Info is the content of the Authentication-Info header
+type Info struct {
+	NextNonce string // optional
+	QOP       QOP    // optional
+	RspAuth   string // optional
+	CNonce    string // optional
+	NC        uint32 // optional
+}
+
+func (i Info) Parse(value string) (Info, error) {
+	err := parseParams(value, func(key, value string) (err error) {
+		switch strings.ToLower(key) {
+		case "nextnonce":
+			i.NextNonce = value
+		case "qop":
+			i.QOP, err = parseQOP(value)
+		case "rspauth":
+			i.RspAuth = value
+		case "cnonce":
+			i.CNonce = value
+		case "nc":
+			i.NC, err = parseNonceCount(value)
+		}
+
+		return err
+	})
+
+	return i, err
+}
+
+func (i Info) String() string {
+	buff := make([]byte, 0, 128)
+
+	if len(i.NextNonce) > 0 {
+		buff = appendParam(buff, "nextnonce", i.NextNonce, true)
+	}
+
+	if len(i.QOP) > 0 {
+		buff = appendParam(buff, "qop", string(i.QOP), false)
+	}
+
+	if len(i.RspAuth) > 0 {
+		buff = appendParam(buff, "rspauth", i.RspAuth, true)
+	}
+
+	if len(i.CNonce) > 0 {
+		buff = appendParam(buff, "cnonce", i.CNonce, true)
+	}
+
+	if i.NC > 0 {
+		buff = appendParam(buff, "nc", formatNonceCount(i.NC), false)
+	}
+
+	return string(buff)
+}
+
+// parseNonceCount parses the nc parameter, which is exactly 8 hex digits
+func parseNonceCount(value string) (uint32, error) {
+	if len(value) != 8 {
+		return 0, ErrBadSyntax
+	}
+
+	nc, err := strconv.ParseUint(value, 16, 32)
+	if err != nil {
+		return 0, ErrBadSyntax
+	}
+
+	return uint32(nc), nil
+}
+
+func formatNonceCount(nc uint32) string {
+	const zeroes = "00000000"
+
+	hex := strconv.FormatUint(uint64(nc), 16)
+
+	return zeroes[len(hex):] + hex
+}
@@ -0,0 +1,158 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChallenge(t *testing.T) {
+	t.Run("parse", func(t *testing.T) {
+		sample := `Digest realm="atlanta.com", domain="sip:ss1.carrier.com", ` +
+			`qop="auth,auth-int,unknown", nonce="f84f1cec41e6cbe5aea9c8e88d359", ` +
+			`opaque="", stale=FALSE, algorithm=md5`
+		challenge, err := Challenge{}.Parse(sample)
+		require.NoError(t, err)
+		require.Equal(t, "atlanta.com", challenge.Realm)
+		require.Equal(t, "sip:ss1.carrier.com", challenge.Domain)
+		require.Equal(t, "f84f1cec41e6cbe5aea9c8e88d359", challenge.Nonce)
+		require.Empty(t, challenge.Opaque)
+		require.False(t, challenge.Stale)
+		require.Equal(t, MD5, challenge.Algorithm)
+		require.Equal(t, []QOP{Auth, AuthInt}, challenge.QOP)
+	})
+
+	t.Run("escaped quotes", func(t *testing.T) {
+		challenge, err := Challenge{}.Parse(`Digest realm="the \"best\" realm",nonce="a\\b"`)
+		require.NoError(t, err)
+		require.Equal(t, `the "best" realm`, challenge.Realm)
+		require.Equal(t, `a\b`, challenge.Nonce)
+		require.Equal(t, `Digest realm="the \"best\" realm", nonce="a\\b"`, challenge.String())
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		challenge := Challenge{
+			Realm:     "biloxi.com",
+			Nonce:     "dcd98b7102dd2f0e8b11d0f600bfb0c093",
+			Opaque:    "5ccc069c403ebaf9f0171e9517f40e41",
+			Stale:     true,
+			Algorithm: SHA512256,
+			QOP:       []QOP{Auth},
+		}
+		require.Equal(t, `Digest realm="biloxi.com", nonce="dcd98b7102dd2f0e8b11d0f600bfb0c093", `+
+			`opaque="5ccc069c403ebaf9f0171e9517f40e41", stale=true, algorithm=SHA-512-256, qop="auth"`,
+			challenge.String())
+		parsed, err := Challenge{}.Parse(challenge.String())
+		require.NoError(t, err)
+		require.Equal(t, challenge, parsed)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for sample, want := range map[string]error{
+			`Basic realm="atlanta.com"`:                ErrUnknownScheme,
+			`Digest realm="atlanta.com"`:               ErrMissingParam,
+			`Digest nonce="abc", opaque="qwer"`:        ErrMissingParam,
+			`Digest realm="atlanta.com, nonce="abc"`:   ErrBadSyntax,
+			`Digest nonce="abc", algorithm=SHA-1`:      ErrUnknownAlgorithm,
+			`Digest nonce="abc" realm="atlanta.com"`:   ErrBadSyntax,
+			`Digest nonce="abc", ="atlanta.com"`:       ErrBadSyntax,
+			`Digest nonce="abc", realm="unterminated`:  ErrBadSyntax,
+			`Digest nonce="abc", realm, opaque="qwer"`: ErrBadSyntax,
+		} {
+			_, err := Challenge{}.Parse(sample)
+			require.ErrorIsf(t, err, want, "sample: %s", sample)
+		}
+	})
+}
+
+func TestCredentials(t *testing.T) {
+	t.Run("parse", func(t *testing.T) {
+		sample := `Digest username="Mufasa", realm="testrealm@host.com", ` +
+			`nonce="dcd98b7102dd2f0e8b11d0f600bfb0c093", uri="/dir/index.html", ` +
+			`qop=auth, nc=00000001, cnonce="0a4f113b", ` +
+			`response="6629fae49393a05397450978507c4ef1", opaque="5ccc069c403ebaf9f0171e9517f40e41"`
+		credentials, err := Credentials{}.Parse(sample)
+		require.NoError(t, err)
+		require.Equal(t, "Mufasa", credentials.Username)
+		require.Equal(t, "testrealm@host.com", credentials.Realm)
+		require.Equal(t, "dcd98b7102dd2f0e8b11d0f600bfb0c093", credentials.Nonce)
+		require.Equal(t, "/dir/index.html", credentials.URI)
+		require.Equal(t, Auth, credentials.QOP)
+		require.Equal(t, uint32(1), credentials.NC)
+		require.Equal(t, "0a4f113b", credentials.CNonce)
+		require.Equal(t, "5ccc069c403ebaf9f0171e9517f40e41", credentials.Opaque)
+		require.True(t, credentials.Verify("GET", "Circle Of Life", nil))
+		require.False(t, credentials.Verify("GET", "Circle of Life", nil))
+	})
+
+	t.Run("sha-256", func(t *testing.T) {
+		// RFC 7616 3.9.1
+		credentials := Credentials{
+			Username:  "Mufasa",
+			Realm:     "http-auth@example.org",
+			Nonce:     "7ypf/xlj9XXwfDPEoM4URrv/xwf94BcCAzFZH4GiTo0v",
+			URI:       "/dir/index.html",
+			Algorithm: SHA256,
+			CNonce:    "f2/wE4q74E6zIJEtWaHKaf5wv/H5QzzpXusqGemxURZJ",
+			QOP:       Auth,
+			NC:        1,
+		}
+		credentials.Sign("GET", "Circle of Life", nil)
+		require.Equal(t, "753927fa0e85d155564e2e272a28d1802ca10daf4496794697cf8db5856cb6c1", credentials.Response)
+
+		parsed, err := Credentials{}.Parse(credentials.String())
+		require.NoError(t, err)
+		require.Equal(t, credentials, parsed)
+	})
+
+	t.Run("without qop", func(t *testing.T) {
+		credentials := Credentials{
+			Username: "bob",
+			Realm:    "biloxi.com",
+			Nonce:    "dcd98b7102dd2f0e8b11d0f600bfb0c093",
+			URI:      "sip:bob@biloxi.com",
+		}
+		credentials.Sign("REGISTER", "zanzibar", nil)
+		require.Equal(t, `Digest username="bob", realm="biloxi.com", `+
+			`nonce="dcd98b7102dd2f0e8b11d0f600bfb0c093", uri="sip:bob@biloxi.com", `+
+			`response="`+credentials.Response+`"`, credentials.String())
+		require.True(t, credentials.Verify("REGISTER", "zanzibar", nil))
+	})
+
+	t.Run("missing params", func(t *testing.T) {
+		for _, sample := range []string{
+			// cnonce
+			`Digest username="bob", realm="biloxi.com", nonce="abc", uri="sip:biloxi.com", ` +
+				`response="0123", qop=auth, nc=00000001`,
+			// nc
+			`Digest username="bob", realm="biloxi.com", nonce="abc", uri="sip:biloxi.com", ` +
+				`response="0123", qop=auth, cnonce="xyz"`,
+			// realm
+			`Digest username="bob", nonce="abc", uri="sip:biloxi.com", response="0123"`,
+		} {
+			_, err := Credentials{}.Parse(sample)
+			require.ErrorIsf(t, err, ErrMissingParam, "sample: %s", sample)
+		}
+	})
+
+	t.Run("bad nonce count", func(t *testing.T) {
+		_, err := Credentials{}.Parse(`Digest username="bob", nonce="abc", uri="sip:biloxi.com", ` +
+			`response="0123", qop=auth, cnonce="xyz", nc=1`)
+		require.ErrorIs(t, err, ErrBadSyntax)
+	})
+}
+
+func TestInfo(t *testing.T) {
+	info, err := Info{}.Parse(`nextnonce="47364c23432d2e131a5fb210812c", qop=auth, ` +
+		`rspauth="0123456789abcdef", cnonce="0a4f113b", nc=0000000a`)
+	require.NoError(t, err)
+	require.Equal(t, Info{
+		NextNonce: "47364c23432d2e131a5fb210812c",
+		QOP:       Auth,
+		RspAuth:   "0123456789abcdef",
+		CNonce:    "0a4f113b",
+		NC:        10,
+	}, info)
+	require.Equal(t, `nextnonce="47364c23432d2e131a5fb210812c", qop=auth, `+
+		`rspauth="0123456789abcdef", cnonce="0a4f113b", nc=0000000a`, info.String())
+}
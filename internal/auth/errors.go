@@ -0,0 +1,11 @@
+package auth
+
+import "errors"
+
+var (
+	ErrBadSyntax        = errors.New("authentication header is malformed")
+	ErrUnknownScheme    = errors.New("received unsupported authentication scheme")
+	ErrUnknownAlgorithm = errors.New("received unsupported digest algorithm")
+	ErrUnknownQOP       = errors.New("received unsupported quality of protection")
+	ErrMissingParam     = errors.New("compulsory digest parameter is missing")
+)
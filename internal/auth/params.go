@@ -0,0 +1,162 @@
+package auth
+
+import (
+	"strings"
+)
+
+// Header names carrying the digest challenges and credentials. See RFC 3261 20.5, 20.7,
+// 20.27, 20.28 and 20.44
+const (
+	WWWAuthenticate    = "WWW-Authenticate"
+	ProxyAuthenticate  = "Proxy-Authenticate"
+	Authorization      = "Authorization"
+	ProxyAuthorization = "Proxy-Authorization"
+	AuthenticationInfo = "Authentication-Info"
+)
+
+const digestScheme = "Digest"
+
+// trimScheme cuts the leading authentication scheme off. Only the Digest one is
+// supported, as it's the only one defined for SIP
+func trimScheme(value string) (string, error) {
+	value = strings.TrimLeft(value, " \t")
+	sp := strings.IndexAny(value, " \t")
+	if sp == -1 {
+		if strings.EqualFold(value, digestScheme) {
+			return "", nil
+		}
+
+		return "", ErrUnknownScheme
+	}
+
+	if !strings.EqualFold(value[:sp], digestScheme) {
+		return "", ErrUnknownScheme
+	}
+
+	return value[sp+1:], nil
+}
+
+// parseParams walks through the comma-separated list of parameters. It follows the
+// following grammar:
+//
+//	param *( LWS "," LWS param )
+//	param = token LWS "=" LWS ( token | quoted-string )
+//
+// Quoted values are passed to the callback already unquoted.
+func parseParams(value string, onParam func(key, value string) error) error {
+	for {
+		value = strings.TrimLeft(value, " \t,")
+		if len(value) == 0 {
+			return nil
+		}
+
+		eq := strings.IndexByte(value, '=')
+		if eq <= 0 {
+			return ErrBadSyntax
+		}
+
+		key := strings.TrimRight(value[:eq], " \t")
+		value = strings.TrimLeft(value[eq+1:], " \t")
+
+		var (
+			paramValue string
+			err        error
+		)
+
+		if len(value) > 0 && value[0] == '"' {
+			paramValue, value, err = unquote(value)
+			if err != nil {
+				return err
+			}
+		} else {
+			end := strings.IndexAny(value, ", \t")
+			if end == -1 {
+				end = len(value)
+			}
+
+			paramValue, value = value[:end], value[end:]
+		}
+
+		if len(key) == 0 || strings.ContainsAny(key, " \t\"") {
+			return ErrBadSyntax
+		}
+
+		if err = onParam(key, paramValue); err != nil {
+			return err
+		}
+
+		value = strings.TrimLeft(value, " \t")
+		if len(value) > 0 && value[0] != ',' {
+			return ErrBadSyntax
+		}
+	}
+}
+
+// unquote parses a quoted-string at the beginning of the value. The escaped characters
+// are unescaped, which requires a copy. Otherwise, the returned string refers to the
+// original value
+func unquote(value string) (unquoted, rest string, err error) {
+	var (
+		buff    []byte
+		escaped bool
+	)
+
+	for i := 1; i < len(value); i++ {
+		switch char := value[i]; {
+		case escaped:
+			buff = append(buff, char)
+			escaped = false
+		case char == '\\':
+			if buff == nil {
+				buff = append(make([]byte, 0, len(value)), value[1:i]...)
+			}
+
+			escaped = true
+		case char == '"':
+			if buff == nil {
+				return value[1:i], value[i+1:], nil
+			}
+
+			return string(buff), value[i+1:], nil
+		default:
+			if buff != nil {
+				buff = append(buff, char)
+			}
+		}
+	}
+
+	return "", "", ErrBadSyntax
+}
+
+// appendQuoted appends the value as a quoted-string, escaping the double quotes and
+// backslashes
+func appendQuoted(buff []byte, value string) []byte {
+	buff = append(buff, '"')
+
+	for i := 0; i < len(value); i++ {
+		if value[i] == '"' || value[i] == '\\' {
+			buff = append(buff, '\\')
+		}
+
+		buff = append(buff, value[i])
+	}
+
+	return append(buff, '"')
+}
+
+// appendParam appends a key=value pair to the digest parameters list. The separator
+// is written only if the buffer already contains any parameter
+func appendParam(buff []byte, key, value string, quoted bool) []byte {
+	if len(buff) > 0 && buff[len(buff)-1] != ' ' {
+		buff = append(buff, ", "...)
+	}
+
+	buff = append(buff, key...)
+	buff = append(buff, '=')
+
+	if quoted {
+		return appendQuoted(buff, value)
+	}
+
+	return append(buff, value...)
+}
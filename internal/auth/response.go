@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"hash"
+)
+
+func (a Algorithm) hash() hash.Hash {
+	switch a {
+	case SHA256, SHA256Sess:
+		return sha256.New()
+	case SHA512256, SHA512256Sess:
+		return sha512.New512_256()
+	default:
+		return md5.New()
+	}
+}
+
+// digest returns the lowercase hex digest of the colon-separated parts
+func (a Algorithm) digest(parts ...string) string {
+	h := a.hash()
+
+	for i, part := range parts {
+		if i > 0 {
+			_, _ = h.Write([]byte{':'})
+		}
+
+		_, _ = h.Write([]byte(part))
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Digest computes the request-digest as defined in RFC 7616 3.4.1. The body is used
+// only with the auth-int quality of protection
+func (c Credentials) Digest(method, password string, body []byte) string {
+	algorithm := c.Algorithm
+	if len(algorithm) == 0 {
+		algorithm = MD5
+	}
+
+	ha1 := algorithm.digest(c.Username, c.Realm, password)
+	if algorithm.Session() {
+		ha1 = algorithm.digest(ha1, c.Nonce, c.CNonce)
+	}
+
+	var ha2 string
+	if c.QOP == AuthInt {
+		ha2 = algorithm.digest(method, c.URI, algorithm.digest(string(body)))
+	} else {
+		ha2 = algorithm.digest(method, c.URI)
+	}
+
+	if len(c.QOP) == 0 {
+		return algorithm.digest(ha1, c.Nonce, ha2)
+	}
+
+	return algorithm.digest(ha1, c.Nonce, formatNonceCount(c.NC), c.CNonce, string(c.QOP), ha2)
+}
+
+// Sign computes the response parameter of the credentials
+func (c *Credentials) Sign(method, password string, body []byte) {
+	c.Response = c.Digest(method, password, body)
+}
+
+// Verify reports whether the response parameter matches the one computed from the
+// password. The comparison is done in constant time
+func (c Credentials) Verify(method, password string, body []byte) bool {
+	want := c.Digest(method, password, body)
+
+	return subtle.ConstantTimeCompare([]byte(want), []byte(c.Response)) == 1
+}
+
+// RspAuth computes the rspauth parameter of the Authentication-Info header, that
+// proves to the client that the server also knows the password. It's computed the same
+// way the response is, except that the method is empty
+func (c Credentials) RspAuth(password string, body []byte) string {
+	return c.Digest("", password, body)
+}
@@ -1,5 +1,7 @@
 package header
 
+import "strings"
+
 // headersPreAlloc is implemented, as we already know that headers will definitely be
 // presented in every request. So we can a bit make the life of GC easier by avoiding
 // extra map allocations (escapes, so this also positively affects cold performance)
@@ -46,6 +48,11 @@ func (h Headers) Set(key string, values ...string) {
 	h.headers[key] = values
 }
 
+// Delete removes the entry with all its values
+func (h Headers) Delete(key string) {
+	delete(h.headers, key)
+}
+
 // Clear clears all the headers
 func (h Headers) Clear() {
 	// fun fact: this will be optimized into a single mapclear() call
@@ -58,3 +65,22 @@ func (h Headers) Clear() {
 func (h Headers) Unwrap() map[string][]string {
 	return h.headers
 }
+
+// Clone returns a deep copy of the headers. Both keys and values are copied, so the
+// result doesn't share any memory with the original and may safely outlive it
+func (h Headers) Clone() Headers {
+	clone := Headers{
+		headers: make(map[string][]string, len(h.headers)),
+	}
+
+	for key, values := range h.headers {
+		owned := make([]string, len(values))
+		for i := range values {
+			owned[i] = strings.Clone(values[i])
+		}
+
+		clone.headers[strings.Clone(key)] = owned
+	}
+
+	return clone
+}
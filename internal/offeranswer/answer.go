@@ -0,0 +1,140 @@
+package offeranswer
+
+import (
+	"github.com/gokiki/sip-server/internal/sdp"
+	"github.com/gokiki/sip-server/internal/sip"
+)
+
+// Answer produces the answer to the received offer. Each offered stream is matched
+// against an unused capability of the same media type and transport protocol; streams
+// without a match or without common codecs are rejected with port 0. If every stream
+// is rejected, the answer is returned together with sip.ErrNotAcceptableHere, and the
+// state of the negotiator stays as it was before the offer
+func (n *Negotiator) Answer(offer sdp.Description) (sdp.Description, error) {
+	if n.pending != nil {
+		// both sides sent offers simultaneously. See RFC 3261 14.2
+		return sdp.Description{}, sip.ErrRequestPending
+	}
+
+	if err := n.checkRemote(offer); err != nil {
+		return sdp.Description{}, err
+	}
+
+	answer := n.baseDescription(offer.Session.Times)
+	answer.Media = make([]sdp.Media, len(offer.Media))
+	used := make([]bool, len(n.caps.Media))
+	accepted := 0
+
+	for i := range offer.Media {
+		media, ok := n.answerMedia(offer, i, used)
+		if ok {
+			accepted++
+		}
+
+		answer.Media[i] = media
+	}
+
+	n.stamp(&answer)
+
+	if accepted == 0 {
+		return answer, sip.ErrNotAcceptableHere
+	}
+
+	n.commitLocal(answer)
+	n.remote = &offer
+
+	return answer, nil
+}
+
+func (n *Negotiator) answerMedia(offer sdp.Description, index int, used []bool) (sdp.Media, bool) {
+	offered := offer.Media[index]
+
+	if offered.Port != 0 {
+		for i, capability := range n.caps.Media {
+			if used[i] || capability.Type != offered.Type || capability.proto() != offered.Proto {
+				continue
+			}
+
+			media, ok := answerFormats(offered, capability)
+			if !ok {
+				continue
+			}
+
+			used[i] = true
+			dir := offer.Direction(index).Reverse()
+			if n.hold {
+				dir = holdDirection(dir)
+			}
+
+			media.Attributes = append(media.Attributes, dir.Attribute())
+
+			return media, true
+		}
+	}
+
+	rejected := sdp.Media{
+		Type:  offered.Type,
+		Port:  0,
+		Proto: offered.Proto,
+	}
+
+	// the formats of a rejected stream don't matter, but at least one must be listed
+	if len(offered.Formats) > 0 {
+		rejected.Formats = []string{offered.Formats[0]}
+	}
+
+	return rejected, false
+}
+
+// answerFormats intersects the offered formats with the supported ones. Offered
+// payload types and order are kept, while format parameters are the local ones
+func answerFormats(offered sdp.Media, capability MediaCapability) (sdp.Media, bool) {
+	media := sdp.Media{
+		Type:  offered.Type,
+		Port:  capability.Port,
+		Proto: offered.Proto,
+	}
+
+	if !offered.Proto.RTP() {
+		for _, format := range offered.Formats {
+			for _, codec := range capability.Codecs {
+				if codec.Name == format {
+					media.Formats = append(media.Formats, format)
+					break
+				}
+			}
+		}
+
+		return media, len(media.Formats) > 0
+	}
+
+	codecs, err := offered.Codecs()
+	if err != nil {
+		return media, false
+	}
+
+	common := make([]sdp.Codec, 0, len(codecs))
+
+	for _, codec := range codecs {
+		for _, local := range capability.Codecs {
+			if !codec.Matches(local) {
+				continue
+			}
+
+			if len(local.Params) > 0 {
+				codec.Params = local.Params
+			}
+
+			common = append(common, codec)
+			break
+		}
+	}
+
+	if len(common) == 0 {
+		return media, false
+	}
+
+	media.SetCodecs(common)
+
+	return media, true
+}
@@ -0,0 +1,31 @@
+package offeranswer
+
+import "github.com/gokiki/sip-server/internal/sdp"
+
+// allowedAnswer reports whether the answered direction is permitted for the offered
+// one. See RFC 3264 6.1
+func allowedAnswer(offered, answered sdp.Direction) bool {
+	switch offered {
+	case sdp.SendOnly:
+		return answered == sdp.RecvOnly || answered == sdp.Inactive
+	case sdp.RecvOnly:
+		return answered == sdp.SendOnly || answered == sdp.Inactive
+	case sdp.Inactive:
+		return answered == sdp.Inactive
+	default:
+		return true
+	}
+}
+
+// holdDirection returns the direction of the held stream. The local party stops
+// receiving, but may still send, e.g. music on hold
+func holdDirection(dir sdp.Direction) sdp.Direction {
+	switch dir {
+	case sdp.SendRecv:
+		return sdp.SendOnly
+	case sdp.RecvOnly:
+		return sdp.Inactive
+	default:
+		return dir
+	}
+}
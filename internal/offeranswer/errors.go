@@ -0,0 +1,14 @@
+package offeranswer
+
+import "errors"
+
+var (
+	ErrNoPendingOffer     = errors.New("received an answer without pending offer")
+	ErrOfferPending       = errors.New("can't make a new offer, as the previous one isn't answered")
+	ErrMediaCountMismatch = errors.New("answer must contain as many media descriptions as the offer")
+	ErrMediaTypeMismatch  = errors.New("answered media type differs from the offered one")
+	ErrProtoMismatch      = errors.New("answered transport protocol differs from the offered one")
+	ErrUnexpectedPort     = errors.New("media rejected in the offer must be rejected in the answer")
+	ErrUnknownFormat      = errors.New("answer contains a format that wasn't offered")
+	ErrDirectionMismatch  = errors.New("answered direction isn't allowed by the offered one")
+)
@@ -0,0 +1,128 @@
+package offeranswer
+
+import (
+	"bytes"
+
+	"github.com/gokiki/sip-server/internal/sdp"
+)
+
+// MediaCapability describes a single kind of stream the local endpoint is able to
+// handle. Each capability is used by at most one stream of the session
+type MediaCapability struct {
+	Type sdp.MediaType
+	// Proto is the transport protocol. Empty means RTP/AVP
+	Proto sdp.TransportProto
+	// Port is the local port the stream is received on
+	Port int
+	// Codecs are listed in the order of preference. Payload types are used only in
+	// offers, answers use the offered ones
+	Codecs []sdp.Codec
+}
+
+func (m MediaCapability) proto() sdp.TransportProto {
+	if len(m.Proto) == 0 {
+		return sdp.RTPAVP
+	}
+
+	return m.Proto
+}
+
+// Capabilities is the local capability set
+type Capabilities struct {
+	// Username is the o= username. Empty means "-"
+	Username string
+	Address  string
+	AddrType sdp.AddrType
+	Media    []MediaCapability
+}
+
+// Negotiator keeps the offer/answer state of a single session, as described in
+// RFC 3264. It isn't safe for concurrent use
+type Negotiator struct {
+	caps   Capabilities
+	origin sdp.Origin
+	hold   bool
+	// local and remote are the last descriptions sent and received
+	local  *sdp.Description
+	remote *sdp.Description
+	// pending is the offer sent, but not answered yet. prevLocal is the description
+	// sent before it, which is restored if the offer is rolled back
+	pending   *sdp.Description
+	prevLocal *sdp.Description
+}
+
+func NewNegotiator(caps Capabilities) *Negotiator {
+	return &Negotiator{
+		caps:   caps,
+		origin: sdp.NewOrigin(caps.Username, caps.AddrType, caps.Address),
+	}
+}
+
+// Hold puts the session on hold or resumes it. It takes effect on the next offer
+func (n *Negotiator) Hold(hold bool) {
+	n.hold = hold
+}
+
+// Local returns the last description sent to the remote party
+func (n *Negotiator) Local() (sdp.Description, bool) {
+	if n.local == nil {
+		return sdp.Description{}, false
+	}
+
+	return *n.local, true
+}
+
+// Remote returns the last description received from the remote party
+func (n *Negotiator) Remote() (sdp.Description, bool) {
+	if n.remote == nil {
+		return sdp.Description{}, false
+	}
+
+	return *n.remote, true
+}
+
+// stamp sets the origin of the description. The version is incremented only if the
+// description differs from the previously sent one, as RFC 3264 8 requires. The
+// negotiator isn't changed until the description is committed
+func (n *Negotiator) stamp(desc *sdp.Description) {
+	desc.Session.Originator = n.origin
+
+	if n.local == nil || bytes.Equal(desc.Marshal(), n.local.Marshal()) {
+		return
+	}
+
+	desc.Session.Originator = n.origin.Next()
+}
+
+// commitLocal remembers the stamped description as the last one sent
+func (n *Negotiator) commitLocal(desc sdp.Description) {
+	n.origin = desc.Session.Originator
+	n.local = &desc
+}
+
+// checkRemote checks the origin of the received description against the previous one
+func (n *Negotiator) checkRemote(desc sdp.Description) error {
+	if n.remote == nil {
+		return nil
+	}
+
+	_, err := desc.ChangeFrom(*n.remote)
+
+	return err
+}
+
+func (n *Negotiator) baseDescription(times []sdp.TimeDescription) sdp.Description {
+	return sdp.Description{
+		Session: sdp.Session{
+			Protocol: "0",
+			Name:     "-",
+			ConnectionInfo: []sdp.ConnectionInfo{{
+				NetType:  sdp.IN,
+				AddrType: n.caps.AddrType,
+				Address:  n.caps.Address,
+				TTL:      -1,
+			}},
+			Times: times,
+		},
+	}
+}
@@ -0,0 +1,155 @@
+package offeranswer
+
+import "github.com/gokiki/sip-server/internal/sdp"
+
+// Offer creates a new offer. The initial offer is made of the capabilities, while
+// re-offers keep the media descriptions of the previously sent description, as
+// RFC 3264 8 forbids removing or reordering them. Only the direction is renewed,
+// depending on whether the session is on hold
+func (n *Negotiator) Offer() (sdp.Description, error) {
+	if n.pending != nil {
+		return sdp.Description{}, ErrOfferPending
+	}
+
+	var offer sdp.Description
+
+	if n.local == nil {
+		offer = n.initialOffer()
+	} else {
+		offer = n.baseDescription(n.local.Session.Times)
+		offer.Media = make([]sdp.Media, len(n.local.Media))
+
+		for i, prev := range n.local.Media {
+			media := prev
+			if media.Port != 0 {
+				dir := sdp.SendRecv
+				if n.hold {
+					dir = sdp.SendOnly
+				}
+
+				media.SetDirection(dir)
+			}
+
+			offer.Media[i] = media
+		}
+	}
+
+	n.stamp(&offer)
+	n.prevLocal = n.local
+	n.commitLocal(offer)
+	n.pending = n.local
+
+	return offer, nil
+}
+
+func (n *Negotiator) initialOffer() sdp.Description {
+	offer := n.baseDescription([]sdp.TimeDescription{{}})
+	offer.Media = make([]sdp.Media, len(n.caps.Media))
+
+	dir := sdp.SendRecv
+	if n.hold {
+		dir = sdp.SendOnly
+	}
+
+	for i, capability := range n.caps.Media {
+		media := sdp.Media{
+			Type:  capability.Type,
+			Port:  capability.Port,
+			Proto: capability.proto(),
+		}
+
+		if media.Proto.RTP() {
+			media.SetCodecs(capability.Codecs)
+		} else {
+			for _, codec := range capability.Codecs {
+				media.Formats = append(media.Formats, codec.Name)
+			}
+		}
+
+		media.Attributes = append(media.Attributes, dir.Attribute())
+		offer.Media[i] = media
+	}
+
+	return offer
+}
+
+// Rollback discards the pending offer, e.g. when it was rejected, so the previously
+// sent description becomes the local one again. The origin version isn't rolled back,
+// as the remote party may have seen the discarded offer already
+func (n *Negotiator) Rollback() {
+	if n.pending == nil {
+		return
+	}
+
+	n.local, n.prevLocal, n.pending = n.prevLocal, nil, nil
+}
+
+// ProcessAnswer validates the received answer against the pending offer, as
+// RFC 3264 6 and 8 require, and completes the exchange
+func (n *Negotiator) ProcessAnswer(answer sdp.Description) error {
+	if n.pending == nil {
+		return ErrNoPendingOffer
+	}
+
+	offer := n.pending
+
+	if len(answer.Media) != len(offer.Media) {
+		return ErrMediaCountMismatch
+	}
+
+	for i, answered := range answer.Media {
+		offered := offer.Media[i]
+
+		if answered.Type != offered.Type {
+			return ErrMediaTypeMismatch
+		}
+
+		if answered.Port == 0 {
+			continue
+		}
+
+		if offered.Port == 0 {
+			return ErrUnexpectedPort
+		}
+
+		if answered.Proto != offered.Proto {
+			return ErrProtoMismatch
+		}
+
+		if !subset(answered.Formats, offered.Formats) {
+			return ErrUnknownFormat
+		}
+
+		if !allowedAnswer(offer.Direction(i), answer.Direction(i)) {
+			return ErrDirectionMismatch
+		}
+	}
+
+	if err := n.checkRemote(answer); err != nil {
+		return err
+	}
+
+	n.remote = &answer
+	n.pending, n.prevLocal = nil, nil
+
+	return nil
+}
+
+func subset(formats, of []string) bool {
+	for _, format := range formats {
+		found := false
+
+		for _, other := range of {
+			if format == other {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
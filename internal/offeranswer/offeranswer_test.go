@@ -0,0 +1,297 @@
+package offeranswer
+
+import (
+	"testing"
+
+	"github.com/gokiki/sip-server/internal/sdp"
+	"github.com/gokiki/sip-server/internal/sip"
+	"github.com/stretchr/testify/require"
+)
+
+func newNegotiator() *Negotiator {
+	return NewNegotiator(Capabilities{
+		Address:  "192.0.2.1",
+		AddrType: sdp.IP4,
+		Media: []MediaCapability{{
+			Type: sdp.Audio,
+			Port: 4000,
+			Codecs: []sdp.Codec{
+				{PayloadType: 8, Name: "PCMA", ClockRate: 8000},
+				{PayloadType: 0, Name: "PCMU", ClockRate: 8000},
+				{
+					PayloadType: 101, Name: "telephone-event", ClockRate: 8000,
+					Params: []sdp.FormatParam{{Value: "0-16"}},
+				},
+			},
+		}},
+	})
+}
+
+func parse(t *testing.T, sample string) sdp.Description {
+	desc, err := sdp.NewParser().Parse([]byte(sample))
+	require.NoError(t, err)
+
+	return desc
+}
+
+const remoteOffer = "v=0\r\n" +
+	"o=alice 2890844526 2890844526 IN IP4 198.51.100.1\r\n" +
+	"s=-\r\n" +
+	"c=IN IP4 198.51.100.1\r\n" +
+	"t=0 0\r\n" +
+	"m=audio 49170 RTP/AVP 111 0 8 97\r\n" +
+	"a=rtpmap:111 opus/48000/2\r\n" +
+	"a=rtpmap:97 telephone-event/8000\r\n" +
+	"a=fmtp:97 0-15\r\n" +
+	"a=sendonly\r\n" +
+	"m=video 51372 RTP/AVP 31\r\n"
+
+func TestAnswer(t *testing.T) {
+	t.Run("intersection and rejection", func(t *testing.T) {
+		n := newNegotiator()
+		answer, err := n.Answer(parse(t, remoteOffer))
+		require.NoError(t, err)
+		require.Len(t, answer.Media, 2)
+
+		audio := answer.Media[0]
+		require.Equal(t, 4000, audio.Port)
+		require.Equal(t, []string{"0", "8", "97"}, audio.Formats)
+		codecs, err := audio.Codecs()
+		require.NoError(t, err)
+		require.Equal(t, "telephone-event", codecs[2].Name)
+		require.Equal(t, []sdp.FormatParam{{Value: "0-16"}}, codecs[2].Params)
+		_, found := audio.Attribute("recvonly")
+		require.True(t, found, "sendonly offer must be answered with recvonly")
+
+		video := answer.Media[1]
+		require.Zero(t, video.Port)
+		require.Equal(t, sdp.Video, video.Type)
+		require.Equal(t, []string{"31"}, video.Formats)
+
+		require.Equal(t, "192.0.2.1", answer.Session.Originator.UnicastAddress)
+		require.False(t, answer.Session.Originator.SameSession(newNegotiator().origin))
+	})
+
+	t.Run("rejected stream doesn't share the offered formats", func(t *testing.T) {
+		offer := parse(t, remoteOffer)
+		offer.Media[1].Formats = []string{"31", "34"}
+		answer, err := newNegotiator().Answer(offer)
+		require.NoError(t, err)
+
+		answer.Media[1].Formats = append(answer.Media[1].Formats, "26")
+		require.Equal(t, []string{"31", "34"}, offer.Media[1].Formats)
+
+		offer = parse(t, remoteOffer)
+		offer.Media[1].Formats = nil
+		answer, err = newNegotiator().Answer(offer)
+		require.NoError(t, err)
+		require.Zero(t, answer.Media[1].Port)
+	})
+
+	t.Run("session refresh", func(t *testing.T) {
+		n := newNegotiator()
+		first, err := n.Answer(parse(t, remoteOffer))
+		require.NoError(t, err)
+		second, err := n.Answer(parse(t, remoteOffer))
+		require.NoError(t, err)
+		require.Equal(t, first.Session.Originator, second.Session.Originator,
+			"unchanged answer must keep the version")
+		require.Equal(t, first.Marshal(), second.Marshal())
+	})
+
+	t.Run("re-offer with changes", func(t *testing.T) {
+		n := newNegotiator()
+		first, err := n.Answer(parse(t, remoteOffer))
+		require.NoError(t, err)
+
+		reoffer := parse(t, remoteOffer)
+		reoffer.Session.Originator.SessVersion = 2890844527
+		reoffer.Media[0].SetDirection(sdp.SendRecv)
+		second, err := n.Answer(reoffer)
+		require.NoError(t, err)
+		_, found := second.Media[0].Attribute("sendrecv")
+		require.True(t, found)
+		require.NotEqual(t, first.Session.Originator.SessVersion, second.Session.Originator.SessVersion)
+	})
+
+	t.Run("origin violations", func(t *testing.T) {
+		n := newNegotiator()
+		_, err := n.Answer(parse(t, remoteOffer))
+		require.NoError(t, err)
+
+		changed := parse(t, remoteOffer)
+		changed.Media[0].Port = 49172
+		_, err = n.Answer(changed)
+		require.Equal(t, sdp.ErrVersionNotBumped, err)
+
+		changed.Session.Originator.SessVersion = 2890844525
+		_, err = n.Answer(changed)
+		require.Equal(t, sdp.ErrVersionRegressed, err)
+
+		changed.Session.Originator.SessId = 1
+		_, err = n.Answer(changed)
+		require.Equal(t, sdp.ErrOriginChanged, err)
+	})
+
+	t.Run("nothing acceptable", func(t *testing.T) {
+		n := newNegotiator()
+		answer, err := n.Answer(parse(t, "v=0\r\n"+
+			"o=- 1 1 IN IP4 198.51.100.1\r\n"+
+			"s=-\r\n"+
+			"t=0 0\r\n"+
+			"m=audio 49170 RTP/AVP 18\r\n"))
+		require.Equal(t, sip.ErrNotAcceptableHere, err)
+		require.Zero(t, answer.Media[0].Port)
+
+		// the failed exchange isn't committed
+		_, found := n.Local()
+		require.False(t, found)
+		_, found = n.Remote()
+		require.False(t, found)
+
+		_, err = n.Answer(parse(t, remoteOffer))
+		require.NoError(t, err)
+	})
+}
+
+const remoteAnswer = "v=0\r\n" +
+	"o=bob 2808844564 2808844564 IN IP4 198.51.100.2\r\n" +
+	"s=-\r\n" +
+	"c=IN IP4 198.51.100.2\r\n" +
+	"t=0 0\r\n" +
+	"m=audio 5004 RTP/AVP 0 101\r\n" +
+	"a=rtpmap:101 telephone-event/8000\r\n"
+
+func TestOffer(t *testing.T) {
+	t.Run("offer and answer", func(t *testing.T) {
+		n := newNegotiator()
+		offer, err := n.Offer()
+		require.NoError(t, err)
+		require.Equal(t, []string{"8", "0", "101"}, offer.Media[0].Formats)
+		_, found := offer.Media[0].Attribute("sendrecv")
+		require.True(t, found)
+
+		_, err = n.Offer()
+		require.Equal(t, ErrOfferPending, err)
+
+		require.NoError(t, n.ProcessAnswer(parse(t, remoteAnswer)))
+		require.Equal(t, ErrNoPendingOffer, n.ProcessAnswer(parse(t, remoteAnswer)))
+	})
+
+	t.Run("invalid answers", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			mutate func(*sdp.Description)
+			want   error
+		}{
+			"media count": {
+				func(d *sdp.Description) { d.Media = append(d.Media, d.Media[0]) },
+				ErrMediaCountMismatch,
+			},
+			"media type": {
+				func(d *sdp.Description) { d.Media[0].Type = sdp.Video },
+				ErrMediaTypeMismatch,
+			},
+			"proto": {
+				func(d *sdp.Description) { d.Media[0].Proto = sdp.RTPSAVP },
+				ErrProtoMismatch,
+			},
+			"unknown format": {
+				func(d *sdp.Description) { d.Media[0].Formats = []string{"18"} },
+				ErrUnknownFormat,
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				n := newNegotiator()
+				_, err := n.Offer()
+				require.NoError(t, err)
+				answer := parse(t, remoteAnswer)
+				tc.mutate(&answer)
+				require.Equal(t, tc.want, n.ProcessAnswer(answer))
+			})
+		}
+	})
+
+	t.Run("hold and resume", func(t *testing.T) {
+		n := newNegotiator()
+		initial, err := n.Offer()
+		require.NoError(t, err)
+		require.NoError(t, n.ProcessAnswer(parse(t, remoteAnswer)))
+
+		n.Hold(true)
+		hold, err := n.Offer()
+		require.NoError(t, err)
+		_, found := hold.Media[0].Attribute("sendonly")
+		require.True(t, found)
+		_, found = hold.Media[0].Attribute("sendrecv")
+		require.False(t, found)
+		require.NotEqual(t, initial.Session.Originator.SessVersion, hold.Session.Originator.SessVersion)
+
+		answer := parse(t, remoteAnswer)
+		answer.Session.Originator.SessVersion = 2808844565
+		answer.Media[0].Attributes = append(answer.Media[0].Attributes, sdp.Attribute{Key: "sendrecv"})
+		require.Equal(t, ErrDirectionMismatch, n.ProcessAnswer(answer))
+
+		answer.Media[0].Attributes[len(answer.Media[0].Attributes)-1] = sdp.Attribute{Key: "recvonly"}
+		require.NoError(t, n.ProcessAnswer(answer))
+
+		n.Hold(false)
+		resume, err := n.Offer()
+		require.NoError(t, err)
+		_, found = resume.Media[0].Attribute("sendrecv")
+		require.True(t, found)
+	})
+
+	t.Run("rejected stream stays rejected", func(t *testing.T) {
+		n := newNegotiator()
+		_, err := n.Answer(parse(t, remoteOffer))
+		require.NoError(t, err)
+
+		reoffer, err := n.Offer()
+		require.NoError(t, err)
+		require.Len(t, reoffer.Media, 2, "m-lines can't be removed in re-offers")
+		require.Zero(t, reoffer.Media[1].Port)
+
+		answer := parse(t, remoteOffer)
+		answer.Session.Originator.SessVersion = 2890844527
+		answer.Media[0].Attributes = []sdp.Attribute{{Key: "recvonly"}}
+		answer.Media[0].Formats = []string{"0"}
+		require.Equal(t, ErrUnexpectedPort, n.ProcessAnswer(answer))
+	})
+
+	t.Run("glare", func(t *testing.T) {
+		n := newNegotiator()
+		_, err := n.Offer()
+		require.NoError(t, err)
+		_, err = n.Answer(parse(t, remoteOffer))
+		require.Equal(t, sip.ErrRequestPending, err)
+
+		n.Rollback()
+		_, found := n.Local()
+		require.False(t, found)
+		_, err = n.Answer(parse(t, remoteOffer))
+		require.NoError(t, err)
+	})
+
+	t.Run("rollback restores the local description", func(t *testing.T) {
+		n := newNegotiator()
+		answer, err := n.Answer(parse(t, remoteOffer))
+		require.NoError(t, err)
+
+		n.Hold(true)
+		_, err = n.Offer()
+		require.NoError(t, err)
+		n.Rollback()
+
+		local, found := n.Local()
+		require.True(t, found)
+		require.Equal(t, answer, local)
+		remote, found := n.Remote()
+		require.True(t, found)
+		require.Equal(t, parse(t, remoteOffer), remote)
+
+		// the version keeps growing, as the discarded offer might have been seen
+		reoffer, err := n.Offer()
+		require.NoError(t, err)
+		require.Greater(t, reoffer.Session.Originator.SessVersion, answer.Session.Originator.SessVersion+1)
+	})
+}
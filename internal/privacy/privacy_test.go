@@ -0,0 +1,180 @@
+package privacy
+
+import (
+	"testing"
+
+	"github.com/gokiki/sip-server/internal/sip"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrustDomain(t *testing.T) {
+	domain, err := NewTrustDomain("proxy.atlanta.com", "192.0.2.10", "10.0.0.0/8", "2001:db8::1")
+	require.NoError(t, err)
+
+	for peer, want := range map[string]bool{
+		"proxy.atlanta.com":      true,
+		"PROXY.atlanta.com:5060": true,
+		"192.0.2.10":             true,
+		"192.0.2.10:5061":        true,
+		"192.0.2.11":             false,
+		"10.20.30.40":            true,
+		"[2001:db8:0::1]:5060":   true,
+		"2001:db8::2":            false,
+		"proxy.biloxi.com":       false,
+	} {
+		require.Equalf(t, want, domain.Trusts(peer), "peer: %s", peer)
+	}
+
+	_, err = NewTrustDomain("10.0.0.0/33")
+	require.Error(t, err)
+}
+
+func newRequest() *sip.Request {
+	request := sip.NewRequest()
+	request.Headers.Add("From", `"Alice" <sip:alice@atlanta.com>;tag=1928301774`)
+	request.Headers.Add("To", "<sip:bob@biloxi.com>")
+	request.Headers.Add("Call-ID", "a84b4c76e66710@pc33.atlanta.com")
+	request.Headers.Add("Contact", "<sip:alice@pc33.atlanta.com>")
+	request.Headers.Add("Subject", "lunch")
+	request.Headers.Add("User-Agent", "Softphone 1.0")
+	request.Headers.Add(sip.PAssertedIdentity, `"Alice" <sip:alice@atlanta.com>, <tel:+14085551212>`)
+	request.Headers.Add(sip.PPreferredIdentity, "<sip:alice@atlanta.com>")
+
+	return request
+}
+
+func TestService(t *testing.T) {
+	domain, err := NewTrustDomain("proxy.atlanta.com")
+	require.NoError(t, err)
+	service := NewService(domain, []byte("secret"), "sip:privacy@proxy.atlanta.com")
+
+	t.Run("inbound from untrusted", func(t *testing.T) {
+		request := newRequest()
+		service.Inbound(request, "198.51.100.1")
+		_, found := request.Headers.Get(sip.PAssertedIdentity)
+		require.False(t, found)
+	})
+
+	t.Run("inbound from untrusted with non-canonical names", func(t *testing.T) {
+		// names are canonicalized by the parser the same way
+		request := sip.NewRequest()
+		request.Headers.Add(sip.CanonicalHeader("f"), "<sip:mallory@evil.com>;tag=1")
+		request.Headers.Add(sip.CanonicalHeader("p-asserted-identity"), "<sip:ceo@bank.com>")
+		request.Headers.Add(sip.CanonicalHeader("P-ASSERTED-IDENTITY"), "<tel:+14085551212>")
+		service.Inbound(request, "198.51.100.1")
+
+		require.Equal(t, map[string][]string{
+			"From": {"<sip:mallory@evil.com>;tag=1"},
+		}, request.Headers.Unwrap())
+	})
+
+	t.Run("inbound from trusted", func(t *testing.T) {
+		request := newRequest()
+		service.Inbound(request, "proxy.atlanta.com:5060")
+		_, found := request.Headers.Get(sip.PAssertedIdentity)
+		require.True(t, found)
+	})
+
+	t.Run("outbound to trusted", func(t *testing.T) {
+		request := newRequest()
+		request.Headers.Add(sip.PrivacyHeader, "header;id")
+		require.NoError(t, service.Outbound(request, "proxy.atlanta.com"))
+		from, _ := request.Headers.Get("From")
+		require.Equal(t, `"Alice" <sip:alice@atlanta.com>;tag=1928301774`, from)
+		_, found := request.Headers.Get(sip.PAssertedIdentity)
+		require.True(t, found)
+	})
+
+	t.Run("id privacy", func(t *testing.T) {
+		request := newRequest()
+		request.Headers.Add(sip.PrivacyHeader, "id")
+		require.NoError(t, service.Outbound(request, "198.51.100.1"))
+		_, found := request.Headers.Get(sip.PAssertedIdentity)
+		require.False(t, found)
+		_, found = request.Headers.Get(sip.PPreferredIdentity)
+		require.False(t, found)
+		from, _ := request.Headers.Get("From")
+		require.Equal(t, `"Alice" <sip:alice@atlanta.com>;tag=1928301774`, from)
+	})
+
+	t.Run("header privacy", func(t *testing.T) {
+		request := newRequest()
+		request.Headers.Add(sip.PrivacyHeader, "header")
+		require.NoError(t, service.Outbound(request, "198.51.100.1"))
+
+		from, _ := request.Headers.Get("From")
+		require.Equal(t, `"Anonymous" <sip:anonymous@anonymous.invalid>;tag=1928301774`, from)
+		contact, _ := request.Headers.Get("Contact")
+		require.Equal(t, "<sip:privacy@proxy.atlanta.com>", contact)
+		callID, _ := request.Headers.Get("Call-ID")
+		require.NotContains(t, callID, "atlanta.com")
+		require.Equal(t, service.obscureCallID("a84b4c76e66710@pc33.atlanta.com"), callID)
+		_, found := request.Headers.Get("Subject")
+		require.False(t, found)
+		_, found = request.Headers.Get("User-Agent")
+		require.False(t, found)
+		// id privacy wasn't requested
+		_, found = request.Headers.Get(sip.PAssertedIdentity)
+		require.True(t, found)
+	})
+
+	t.Run("header privacy with compact forms", func(t *testing.T) {
+		request := sip.NewRequest()
+		request.Headers.Add(sip.CanonicalHeader("f"), `"Alice" <sip:alice@atlanta.com>;tag=1928301774`)
+		request.Headers.Add(sip.CanonicalHeader("i"), "a84b4c76e66710@pc33.atlanta.com")
+		request.Headers.Add(sip.CanonicalHeader("m"), "<sip:alice@pc33.atlanta.com>")
+		request.Headers.Add(sip.CanonicalHeader("s"), "lunch")
+		request.Headers.Add(sip.CanonicalHeader("privacy"), "header")
+		require.NoError(t, service.Outbound(request, "198.51.100.1"))
+
+		headers := request.Headers.Unwrap()
+		require.Equal(t, []string{`"Anonymous" <sip:anonymous@anonymous.invalid>;tag=1928301774`}, headers["From"])
+		require.Equal(t, []string{"<sip:privacy@proxy.atlanta.com>"}, headers["Contact"])
+		require.Equal(t, []string{service.obscureCallID("a84b4c76e66710@pc33.atlanta.com")}, headers["Call-ID"])
+
+		for _, compact := range []string{"f", "i", "m", "s"} {
+			require.NotContains(t, headers, compact)
+		}
+	})
+
+	t.Run("call-id round trip", func(t *testing.T) {
+		const original = "a84b4c76e66710@pc33.atlanta.com"
+		outbound := newRequest()
+		outbound.Headers.Add(sip.PrivacyHeader, "header")
+		require.NoError(t, service.Outbound(outbound, "198.51.100.1"))
+		obscured, _ := outbound.Headers.Get("Call-ID")
+		require.NotEqual(t, original, obscured)
+
+		// the BYE of the peer carries the obscured Call-ID
+		inbound := sip.NewRequest()
+		inbound.Headers.Add("Call-ID", obscured)
+		service.Inbound(inbound, "198.51.100.1")
+		callID, _ := inbound.Headers.Get("Call-ID")
+		require.Equal(t, original, callID)
+
+		response := sip.NewResponse()
+		response.Headers.Add("Call-ID", obscured)
+		service.InboundResponse(response)
+		callID, _ = response.Headers.Get("Call-ID")
+		require.Equal(t, original, callID)
+
+		// values not produced by the service are left as is
+		for _, value := range []string{
+			original,
+			"garbage@anonymous.invalid",
+			NewService(TrustDomain{}, []byte("other"), "").obscureCallID(original),
+		} {
+			response := sip.NewResponse()
+			response.Headers.Add("Call-ID", value)
+			service.InboundResponse(response)
+			callID, _ = response.Headers.Get("Call-ID")
+			require.Equal(t, value, callID)
+		}
+	})
+
+	t.Run("critical session privacy", func(t *testing.T) {
+		request := newRequest()
+		request.Headers.Add(sip.PrivacyHeader, "session;critical")
+		require.Equal(t, sip.ErrServerInternalError, service.Outbound(request, "198.51.100.1"))
+	})
+}
@@ -0,0 +1,208 @@
+package privacy
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+
+	"github.com/gokiki/sip-server/internal/header"
+	"github.com/gokiki/sip-server/internal/sip"
+)
+
+const (
+	anonymousDisplayName = "Anonymous"
+	anonymousURI         = "sip:anonymous@anonymous.invalid"
+	anonymousHost        = "anonymous.invalid"
+)
+
+// identifyingHeaders may reveal the identity of the user, so they're removed when the
+// header privacy is requested. See RFC 3323 5.1. Via and Record-Route reveal the hosts
+// too, but they aren't touched: responses and mid-dialog requests are routed by them,
+// so hiding them requires the transaction state, which is out of the service's scope
+var identifyingHeaders = [...]string{
+	"Call-Info", "In-Reply-To", "Organization", "Reply-To", "Subject", "User-Agent",
+}
+
+// Service is the privacy service of RFC 3323 combined with the identity handling of
+// RFC 3325. It sanitizes asserted identities of requests entering the trust domain,
+// and applies the requested privacy to requests leaving it
+type Service struct {
+	domain TrustDomain
+	// nonceKey and callIDs key the Call-ID obfuscation. The same Call-ID is always mapped
+	// into the same value, the original one can't be guessed, but can be restored by the
+	// service itself
+	nonceKey []byte
+	callIDs  cipher.AEAD
+	// contact replaces the Contact of requests with header privacy. It must route back
+	// to the service. If empty, the Contact is replaced by the anonymous URI
+	contact string
+}
+
+func NewService(domain TrustDomain, secret []byte, contact string) *Service {
+	// neither can fail, as the key is always 32 bytes long
+	block, _ := aes.NewCipher(deriveKey(secret, "call-id key"))
+	callIDs, _ := cipher.NewGCM(block)
+
+	return &Service{
+		domain:   domain,
+		nonceKey: deriveKey(secret, "call-id nonce"),
+		callIDs:  callIDs,
+		contact:  contact,
+	}
+}
+
+func deriveKey(secret []byte, label string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write([]byte(label))
+
+	return mac.Sum(nil)
+}
+
+// Inbound sanitizes the request received from the peer. Identities asserted by peers
+// outside the trust domain can't be relied on, so they're removed. The Call-ID obscured
+// by the header privacy is restored, so the request matches the dialog again
+func (s *Service) Inbound(request *sip.Request, peer string) {
+	if !s.domain.Trusts(peer) {
+		request.Headers.Delete(sip.PAssertedIdentity)
+	}
+
+	s.restoreCallID(request.Headers)
+}
+
+// InboundResponse restores the Call-ID of the response to the request, which was
+// obscured by the header privacy
+func (s *Service) InboundResponse(response *sip.Response) {
+	s.restoreCallID(response.Headers)
+}
+
+// Outbound applies the privacy requested by the Privacy header to the request,
+// forwarded to the peer. Nothing is done if the peer belongs to the trust domain. If
+// the privacy is critical, but some of the requested types can't be provided, the
+// request must be rejected with the returned error
+func (s *Service) Outbound(request *sip.Request, peer string) error {
+	if s.domain.Trusts(peer) {
+		return nil
+	}
+
+	// P-Preferred-Identity is meaningful only for the first hop
+	request.Headers.Delete(sip.PPreferredIdentity)
+
+	var privacy sip.Privacy
+	if value, found := request.Headers.Get(sip.PrivacyHeader); found {
+		var err error
+		if privacy, err = privacy.Parse(value); err != nil {
+			return err
+		}
+	}
+
+	if privacy.Has(sip.PrivacyCritical) && privacy.Has(sip.PrivacySession) {
+		// anonymizing the media requires relaying it, which isn't done here
+		return sip.ErrServerInternalError
+	}
+
+	if privacy.Has(sip.PrivacyID) {
+		request.Headers.Delete(sip.PAssertedIdentity)
+	}
+
+	if privacy.Has(sip.PrivacyUser) || privacy.Has(sip.PrivacyHeaders) {
+		if err := anonymizeFrom(request); err != nil {
+			return err
+		}
+	}
+
+	if privacy.Has(sip.PrivacyHeaders) {
+		s.anonymizeHeaders(request)
+	}
+
+	return nil
+}
+
+// anonymizeFrom replaces the From header with the anonymous one. The tag is kept, as
+// it's needed to match the dialog
+func anonymizeFrom(request *sip.Request) error {
+	value, found := request.Headers.Get("From")
+	if !found {
+		return sip.ErrBadRequest
+	}
+
+	from, err := sip.Address{}.Parse(value)
+	if err != nil {
+		return err
+	}
+
+	anonymous := sip.Address{
+		DisplayName: anonymousDisplayName,
+		URI:         anonymousURI,
+	}
+
+	if tag, found := from.Param("tag"); found {
+		anonymous.Params = "tag=" + tag
+	}
+
+	request.Headers.Set("From", anonymous.String())
+
+	return nil
+}
+
+func (s *Service) anonymizeHeaders(request *sip.Request) {
+	for _, key := range identifyingHeaders {
+		request.Headers.Delete(key)
+	}
+
+	if _, found := request.Headers.Get("Contact"); found {
+		contact := s.contact
+		if len(contact) == 0 {
+			contact = anonymousURI
+		}
+
+		request.Headers.Set("Contact", sip.Address{URI: contact}.String())
+	}
+
+	if callID, found := request.Headers.Get("Call-ID"); found {
+		request.Headers.Set("Call-ID", s.obscureCallID(callID))
+	}
+}
+
+// obscureCallID maps the Call-ID into a value that doesn't reveal the host of the user.
+// The mapping is stable, so all the requests of the dialog get the same Call-ID. The
+// original Call-ID is encrypted into the value, so it can be restored by revealCallID
+func (s *Service) obscureCallID(callID string) string {
+	mac := hmac.New(sha256.New, s.nonceKey)
+	_, _ = mac.Write([]byte(callID))
+	// the nonce is derived from the Call-ID, so it's reused only for the same plaintext
+	nonce := mac.Sum(nil)[:s.callIDs.NonceSize()]
+	sealed := s.callIDs.Seal(nonce, nonce, []byte(callID), nil)
+
+	return base64.RawURLEncoding.EncodeToString(sealed) + "@" + anonymousHost
+}
+
+// revealCallID returns the original Call-ID, if the value was produced by obscureCallID
+func (s *Service) revealCallID(value string) (string, bool) {
+	if !strings.HasSuffix(value, "@"+anonymousHost) {
+		return "", false
+	}
+
+	sealed, err := base64.RawURLEncoding.DecodeString(value[:len(value)-len(anonymousHost)-1])
+	nonceSize := s.callIDs.NonceSize()
+	if err != nil || len(sealed) < nonceSize {
+		return "", false
+	}
+
+	callID, err := s.callIDs.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return "", false
+	}
+
+	return string(callID), true
+}
+
+func (s *Service) restoreCallID(headers header.Headers) {
+	if value, found := headers.Get("Call-ID"); found {
+		if callID, ok := s.revealCallID(value); ok {
+			headers.Set("Call-ID", callID)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package privacy
+
+import (
+	"net"
+	"strings"
+)
+
+// TrustDomain is the set of peers that are trusted to assert identities, and to which
+// the asserted identities may be passed. See RFC 3324
+type TrustDomain struct {
+	hosts    map[string]struct{}
+	networks []*net.IPNet
+}
+
+// NewTrustDomain returns the trust domain consisting of the peers. Each peer is either
+// a host name, an IP address or a network in the CIDR notation
+func NewTrustDomain(peers ...string) (TrustDomain, error) {
+	domain := TrustDomain{
+		hosts: make(map[string]struct{}, len(peers)),
+	}
+
+	for _, peer := range peers {
+		if strings.IndexByte(peer, '/') != -1 {
+			_, network, err := net.ParseCIDR(peer)
+			if err != nil {
+				return domain, err
+			}
+
+			domain.networks = append(domain.networks, network)
+			continue
+		}
+
+		if ip := net.ParseIP(peer); ip != nil {
+			// normalize, so different notations of the same IPv6 address match
+			peer = ip.String()
+		}
+
+		domain.hosts[strings.ToLower(peer)] = struct{}{}
+	}
+
+	return domain, nil
+}
+
+// Trusts reports whether the peer belongs to the trust domain. The peer is a host
+// name or an IP address, optionally followed by a port
+func (t TrustDomain) Trusts(peer string) bool {
+	if host, _, err := net.SplitHostPort(peer); err == nil {
+		peer = host
+	}
+
+	ip := net.ParseIP(strings.Trim(peer, "[]"))
+	if ip != nil {
+		peer = ip.String()
+	}
+
+	if _, found := t.hosts[strings.ToLower(peer)]; found {
+		return true
+	}
+
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range t.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,103 @@
+package sdp
+
+import "strconv"
+
+// rtcpFraction is the share of the session bandwidth RTCP takes by default. See
+// RFC 3550 6.2
+const rtcpFraction = 20
+
+// Bandwidth returns the value of the first media-level bandwidth of the type
+func (m Media) Bandwidth(typ BandwidthType) (int, bool) {
+	return findBandwidth(m.BandwidthInfo, typ)
+}
+
+// Bandwidth returns the value of the first session-level bandwidth of the type
+func (s Session) Bandwidth(typ BandwidthType) (int, bool) {
+	return findBandwidth(s.BandwidthInfo, typ)
+}
+
+func findBandwidth(bandwidths []Bandwidth, typ BandwidthType) (int, bool) {
+	for _, bandwidth := range bandwidths {
+		if bandwidth.Type != typ {
+			continue
+		}
+
+		if len(bandwidth.Raw) == 0 {
+			return bandwidth.Value, true
+		}
+
+		// values of unknown types aren't necessarily numbers
+		if value, err := strconv.Atoi(bandwidth.Raw); err == nil && value >= 0 {
+			return value, true
+		}
+	}
+
+	return 0, false
+}
+
+// MediaBandwidth returns the bandwidth of the i-th media in bits per second, as it's
+// needed for admission control. AS is preferred, as it includes the transport
+// overhead, and TIAS is used otherwise. If the media has neither, the session-level
+// AS applies
+func (d Description) MediaBandwidth(i int) (bps uint64, found bool) {
+	media := d.Media[i]
+
+	if kbps, found := media.Bandwidth(AS); found {
+		return uint64(kbps) * 1000, true
+	}
+
+	if bps, found := media.Bandwidth(TIAS); found {
+		return uint64(bps), true
+	}
+
+	if kbps, found := d.Session.Bandwidth(AS); found {
+		return uint64(kbps) * 1000, true
+	}
+
+	return 0, false
+}
+
+// RTCPBandwidth returns the RTCP bandwidth of the i-th media in bits per second. It's
+// the sum of RS and RR, if any is specified, and 5% of the media bandwidth otherwise
+func (d Description) RTCPBandwidth(i int) (bps uint64, found bool) {
+	media := d.Media[i]
+	rs, rsFound := media.Bandwidth(RS)
+	rr, rrFound := media.Bandwidth(RR)
+
+	if rsFound || rrFound {
+		return uint64(rs) + uint64(rr), true
+	}
+
+	if bps, found = d.MediaBandwidth(i); found {
+		return bps / rtcpFraction, true
+	}
+
+	return 0, false
+}
+
+// TotalBandwidth returns the bandwidth of the whole session in bits per second. It's
+// the sum of bandwidths of the accepted media, limited by the session-level CT. If
+// the bandwidth of any media is unknown, the session-level CT alone is returned
+func (d Description) TotalBandwidth() (bps uint64, found bool) {
+	ct, ctFound := d.Session.Bandwidth(CT)
+	limit := uint64(ct) * 1000
+
+	for i, media := range d.Media {
+		if media.Port == 0 {
+			continue
+		}
+
+		mediaBps, found := d.MediaBandwidth(i)
+		if !found {
+			return limit, ctFound
+		}
+
+		bps += mediaBps
+	}
+
+	if ctFound && limit < bps {
+		bps = limit
+	}
+
+	return bps, true
+}
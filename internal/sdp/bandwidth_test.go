@@ -0,0 +1,81 @@
+package sdp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBandwidth(t *testing.T) {
+	t.Run("modifiers round-trip", func(t *testing.T) {
+		for _, value := range []string{"CT:128", "AS:64", "TIAS:64000", "RS:800", "RR:2400", "X-YZ:128", "X-FOO:0128", "FOO:bar"} {
+			bandwidth, err := Bandwidth{}.Parse(value)
+			require.NoError(t, err, value)
+			require.Equal(t, value, string(bandwidth.AppendTo(nil)))
+		}
+
+		bandwidth, err := Bandwidth{}.Parse("X-YZ:128")
+		require.NoError(t, err)
+		require.Equal(t, Bandwidth{Type: "X-YZ", Value: 128, Raw: "128"}, bandwidth)
+		require.True(t, bandwidth.Type.Experimental())
+		require.False(t, bandwidth.Type.Known())
+
+		media := Media{BandwidthInfo: []Bandwidth{bandwidth, {Type: "X-FOO", Raw: "bar"}}}
+		value, found := media.Bandwidth("X-YZ")
+		require.True(t, found)
+		require.Equal(t, 128, value)
+		_, found = media.Bandwidth("X-FOO")
+		require.False(t, found)
+
+		for _, value := range []string{"AS", "TIAS:fast", ":64", "RR:-1"} {
+			_, err := Bandwidth{}.Parse(value)
+			require.Equal(t, ErrBadSyntax, err, value)
+		}
+	})
+
+	t.Run("admission control", func(t *testing.T) {
+		desc, err := NewParser().Parse([]byte("v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=-\r\n" +
+			"c=IN IP4 192.0.2.1\r\n" +
+			"b=CT:2000\r\n" +
+			"b=AS:100\r\n" +
+			"t=0 0\r\n" +
+			"m=audio 49170 RTP/AVP 0\r\n" +
+			"b=TIAS:64000\r\n" +
+			"b=RS:800\r\n" +
+			"b=RR:2400\r\n" +
+			"m=video 51372 RTP/AVP 31\r\n" +
+			"b=AS:1500\r\n" +
+			"m=text 51374 RTP/AVP 98\r\n" +
+			"m=video 0 RTP/AVP 31\r\n" +
+			"b=AS:5000\r\n"))
+		require.NoError(t, err)
+
+		for i, want := range []uint64{64000, 1500000, 100000, 5000000} {
+			bps, found := desc.MediaBandwidth(i)
+			require.True(t, found)
+			require.Equal(t, want, bps, i)
+		}
+
+		rtcp, found := desc.RTCPBandwidth(0)
+		require.True(t, found)
+		require.Equal(t, uint64(3200), rtcp)
+		rtcp, found = desc.RTCPBandwidth(1)
+		require.True(t, found)
+		require.Equal(t, uint64(75000), rtcp)
+
+		total, found := desc.TotalBandwidth()
+		require.True(t, found)
+		require.Equal(t, uint64(1664000), total)
+
+		desc.Session.BandwidthInfo = desc.Session.BandwidthInfo[:1]
+		total, found = desc.TotalBandwidth()
+		require.True(t, found)
+		require.Equal(t, uint64(2000000), total, "unknown media bandwidth falls back to CT")
+
+		desc.Session.BandwidthInfo = nil
+		_, found = desc.TotalBandwidth()
+		require.False(t, found)
+	})
+}
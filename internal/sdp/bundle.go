@@ -0,0 +1,206 @@
+package sdp
+
+import "strings"
+
+// Semantics is the semantics of the media grouping. See RFC 5888 5
+type Semantics string
+
+const (
+	Bundle  Semantics = "BUNDLE"
+	LipSync Semantics = "LS"
+	FlowID  Semantics = "FID"
+)
+
+// Group is the value of the group attribute, following the grammar:
+//
+//	group-attribute = "a=group:" semantics *(SP identification-tag)
+type Group struct {
+	Semantics Semantics
+	// IDs are the identification tags (mid values) of the grouped media. For BUNDLE
+	// the first one is the tagged m-line, whose transport is shared by the others
+	IDs []string
+}
+
+func (g Group) Parse(value string) (Group, error) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return g, ErrBadSyntax
+	}
+
+	g.Semantics = Semantics(fields[0])
+	if len(fields) > 1 {
+		g.IDs = fields[1:]
+	}
+
+	return g, nil
+}
+
+func (g Group) AppendTo(buff []byte) []byte {
+	buff = append(buff, g.Semantics...)
+
+	for _, id := range g.IDs {
+		buff = append(buff, ' ')
+		buff = append(buff, id...)
+	}
+
+	return buff
+}
+
+// Contains reports whether the media with the mid is a member of the group
+func (g Group) Contains(mid string) bool {
+	for _, id := range g.IDs {
+		if id == mid {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Groups returns the session-level group attributes
+func (s Session) Groups() []Group {
+	var groups []Group
+
+	for _, attr := range s.Attributes {
+		if attr.Key != "group" {
+			continue
+		}
+
+		group, err := Group{}.Parse(attr.Value)
+		if err != nil {
+			// the semantics is missing, so the group means nothing
+			continue
+		}
+
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
+// MID returns the media identification tag. See RFC 5888 4
+func (m Media) MID() (string, bool) {
+	return m.Attribute("mid")
+}
+
+// RTCPMux reports whether RTP and RTCP are multiplexed on a single port. See RFC 5761
+func (m Media) RTCPMux() bool {
+	_, found := m.Attribute("rtcp-mux")
+	return found
+}
+
+// RTCPRsize reports whether reduced-size RTCP is supported. See RFC 5506
+func (m Media) RTCPRsize() bool {
+	_, found := m.Attribute("rtcp-rsize")
+	return found
+}
+
+// BundleOnly reports whether the media may be used only within a BUNDLE group. Such
+// media are offered with port 0. See RFC 8843 6
+func (m Media) BundleOnly() bool {
+	_, found := m.Attribute("bundle-only")
+	return found
+}
+
+// MediaByMID returns the index of the media with the identification tag
+func (d Description) MediaByMID(mid string) (int, bool) {
+	for i, media := range d.Media {
+		if id, found := media.MID(); found && id == mid {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// BundleGroup returns the BUNDLE group the i-th media belongs to
+func (d Description) BundleGroup(i int) (Group, bool) {
+	mid, found := d.Media[i].MID()
+	if !found {
+		return Group{}, false
+	}
+
+	for _, group := range d.Session.Groups() {
+		if group.Semantics == Bundle && group.Contains(mid) {
+			return group, true
+		}
+	}
+
+	return Group{}, false
+}
+
+// Transport is the transport the media is actually sent over
+type Transport struct {
+	// Index is the index of the media, whose transport is used
+	Index   int
+	Address string
+	Port    int
+	ICE     ICECredentials
+	RTCPMux bool
+}
+
+// Transport resolves the transport of the i-th media. Media within a BUNDLE group
+// share the transport of the tagged m-line, which is the first one of the group,
+// as RFC 8843 7.2.1 and 7.3.1 define. If the tagged m-line is rejected, the next
+// m-line of the group takes over, the same way the answerer picks its tagged m-line.
+// Other media use their own transport.
+//
+// ErrNoTransport is returned for rejected media, BUNDLE groups whose m-lines are all
+// rejected, and bundle-only media outside of a BUNDLE group.
+func (d Description) Transport(i int) (Transport, error) {
+	index := i
+
+	if group, found := d.BundleGroup(i); found {
+		var err error
+		if index, err = d.bundleTagged(group); err != nil {
+			return Transport{}, err
+		}
+	}
+
+	media := d.Media[index]
+	if media.Port == 0 {
+		return Transport{}, ErrNoTransport
+	}
+
+	transport := Transport{
+		Index:   index,
+		Port:    media.Port,
+		RTCPMux: media.RTCPMux(),
+	}
+
+	if infos := d.connectionInfo(media); len(infos) > 0 {
+		transport.Address = infos[0].Address
+	}
+
+	transport.ICE, _ = d.ICECredentials(index)
+
+	return transport, nil
+}
+
+// bundleTagged returns the index of the m-line, whose transport the BUNDLE group
+// uses. That's the first m-line of the group with a non-zero port, as the tagged
+// m-line is never bundle-only
+func (d Description) bundleTagged(group Group) (int, error) {
+	for _, mid := range group.IDs {
+		index, found := d.MediaByMID(mid)
+		if !found {
+			return 0, ErrUnknownMID
+		}
+
+		if d.Media[index].Port != 0 {
+			return index, nil
+		}
+	}
+
+	return 0, ErrNoTransport
+}
+
+// connectionInfo returns the connection info effective for the media. Media-level
+// fields take precedence over the session-level ones
+func (d Description) connectionInfo(media Media) []ConnectionInfo {
+	if len(media.ConnectionInfo) > 0 {
+		return media.ConnectionInfo
+	}
+
+	return d.Session.ConnectionInfo
+}
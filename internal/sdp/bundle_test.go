@@ -0,0 +1,104 @@
+package sdp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle(t *testing.T) {
+	desc, err := NewParser().Parse([]byte("v=0\r\n" +
+		"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+		"s=-\r\n" +
+		"c=IN IP4 192.0.2.1\r\n" +
+		"t=0 0\r\n" +
+		"a=group:BUNDLE a v d\r\n" +
+		"a=group:LS a v\r\n" +
+		"a=ice-ufrag:8hhY\r\n" +
+		"a=ice-pwd:asd88fgpdd777uzjYhagZg\r\n" +
+		"m=audio 10000 UDP/TLS/RTP/SAVPF 0\r\n" +
+		"a=mid:a\r\n" +
+		"a=rtcp-mux\r\n" +
+		"a=rtcp-rsize\r\n" +
+		"m=video 10002 UDP/TLS/RTP/SAVPF 31\r\n" +
+		"c=IN IP4 192.0.2.2\r\n" +
+		"a=mid:v\r\n" +
+		"a=rtcp-mux\r\n" +
+		"m=application 0 UDP/DTLS/SCTP webrtc-datachannel\r\n" +
+		"a=mid:d\r\n" +
+		"a=bundle-only\r\n" +
+		"m=audio 20000 RTP/AVP 0\r\n" +
+		"c=IN IP4 192.0.2.3\r\n" +
+		"a=mid:x\r\n" +
+		"m=video 0 RTP/AVP 31\r\n" +
+		"a=bundle-only\r\n"))
+	require.NoError(t, err)
+
+	t.Run("groups", func(t *testing.T) {
+		groups := desc.Session.Groups()
+		require.Equal(t, []Group{
+			{Semantics: Bundle, IDs: []string{"a", "v", "d"}},
+			{Semantics: LipSync, IDs: []string{"a", "v"}},
+		}, groups)
+		require.Equal(t, "BUNDLE a v d", string(groups[0].AppendTo(nil)))
+
+		index, found := desc.MediaByMID("d")
+		require.True(t, found)
+		require.Equal(t, 2, index)
+		require.True(t, desc.Media[index].BundleOnly())
+		require.True(t, desc.Media[0].RTCPMux())
+		require.True(t, desc.Media[0].RTCPRsize())
+		require.False(t, desc.Media[1].RTCPRsize())
+	})
+
+	t.Run("transport resolution", func(t *testing.T) {
+		tagged := Transport{
+			Index:   0,
+			Address: "192.0.2.1",
+			Port:    10000,
+			ICE:     ICECredentials{Ufrag: "8hhY", Pwd: "asd88fgpdd777uzjYhagZg"},
+			RTCPMux: true,
+		}
+
+		for i := 0; i < 3; i++ {
+			transport, err := desc.Transport(i)
+			require.NoError(t, err)
+			require.Equal(t, tagged, transport, i)
+		}
+
+		transport, err := desc.Transport(3)
+		require.NoError(t, err)
+		require.Equal(t, 3, transport.Index)
+		require.Equal(t, "192.0.2.3", transport.Address)
+		require.Equal(t, 20000, transport.Port)
+		require.False(t, transport.RTCPMux)
+
+		_, err = desc.Transport(4)
+		require.Equal(t, ErrNoTransport, err)
+	})
+
+	t.Run("rejected tagged m-line", func(t *testing.T) {
+		rejected := desc
+		rejected.Media = append([]Media(nil), desc.Media...)
+		rejected.Media[0].Port = 0
+
+		for i := 0; i < 3; i++ {
+			transport, err := rejected.Transport(i)
+			require.NoError(t, err)
+			require.Equal(t, 1, transport.Index, i)
+			require.Equal(t, "192.0.2.2", transport.Address)
+			require.Equal(t, 10002, transport.Port)
+		}
+
+		rejected.Media[1].Port = 0
+		_, err := rejected.Transport(2)
+		require.Equal(t, ErrNoTransport, err)
+	})
+
+	t.Run("unknown tagged mid", func(t *testing.T) {
+		broken := desc
+		broken.Session.Attributes = []Attribute{{Key: "group", Value: "BUNDLE z a"}}
+		_, err := broken.Transport(0)
+		require.Equal(t, ErrUnknownMID, err)
+	})
+}
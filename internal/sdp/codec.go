@@ -0,0 +1,357 @@
+package sdp
+
+import (
+	"strconv"
+	"strings"
+)
+
+// RTPMap is the value of the rtpmap attribute, following the grammar:
+//
+//	rtpmap-value = payload-type SP encoding-name "/" clock-rate [ "/" encoding-params ]
+type RTPMap struct {
+	PayloadType uint8
+	Encoding    string
+	ClockRate   int
+	// Channels is zero, if encoding parameters were omitted
+	Channels int
+}
+
+func (r RTPMap) Parse(value string) (RTPMap, error) {
+	sp := strings.IndexByte(value, ' ')
+	if sp == -1 {
+		return r, ErrBadSyntax
+	}
+
+	var err error
+	if r.PayloadType, err = parsePayloadType(value[:sp]); err != nil {
+		return r, err
+	}
+
+	value = strings.TrimLeft(value[sp+1:], " ")
+
+	slash := strings.IndexByte(value, '/')
+	if slash <= 0 {
+		return r, ErrBadSyntax
+	}
+
+	r.Encoding, value = value[:slash], value[slash+1:]
+
+	if slash = strings.IndexByte(value, '/'); slash != -1 {
+		if r.Channels, err = strconv.Atoi(value[slash+1:]); err != nil || r.Channels <= 0 {
+			return r, ErrBadSyntax
+		}
+
+		value = value[:slash]
+	}
+
+	if r.ClockRate, err = strconv.Atoi(value); err != nil || r.ClockRate <= 0 {
+		return r, ErrBadSyntax
+	}
+
+	return r, nil
+}
+
+func (r RTPMap) AppendTo(buff []byte) []byte {
+	buff = strconv.AppendUint(buff, uint64(r.PayloadType), 10)
+	buff = append(buff, ' ')
+	buff = append(buff, r.Encoding...)
+	buff = append(buff, '/')
+	buff = strconv.AppendInt(buff, int64(r.ClockRate), 10)
+
+	if r.Channels > 0 {
+		buff = append(buff, '/')
+		buff = strconv.AppendInt(buff, int64(r.Channels), 10)
+	}
+
+	return buff
+}
+
+// FormatParam is a single parameter of the fmtp attribute. Parameters that are not
+// key-value pairs (like 0-15 of telephone-event) have an empty key
+type FormatParam struct {
+	Key   string
+	Value string
+}
+
+// FormatParams is the value of the fmtp attribute, following the grammar:
+//
+//	fmtp-value = fmt SP format-specific-params
+//
+// The format-specific parameters are parsed as a semicolon-separated list.
+type FormatParams struct {
+	PayloadType uint8
+	Params      []FormatParam
+}
+
+func (f FormatParams) Parse(value string) (FormatParams, error) {
+	sp := strings.IndexByte(value, ' ')
+	if sp == -1 {
+		return f, ErrBadSyntax
+	}
+
+	var err error
+	if f.PayloadType, err = parsePayloadType(value[:sp]); err != nil {
+		return f, err
+	}
+
+	for _, param := range strings.Split(value[sp+1:], ";") {
+		if param = strings.TrimSpace(param); len(param) == 0 {
+			continue
+		}
+
+		if eq := strings.IndexByte(param, '='); eq != -1 {
+			f.Params = append(f.Params, FormatParam{
+				Key:   strings.TrimSpace(param[:eq]),
+				Value: strings.TrimSpace(param[eq+1:]),
+			})
+		} else {
+			f.Params = append(f.Params, FormatParam{Value: param})
+		}
+	}
+
+	return f, nil
+}
+
+// Get returns the value of the parameter. Keys are case-insensitive
+func (f FormatParams) Get(key string) (value string, found bool) {
+	for _, param := range f.Params {
+		if strings.EqualFold(param.Key, key) {
+			return param.Value, true
+		}
+	}
+
+	return "", false
+}
+
+func (f FormatParams) AppendTo(buff []byte) []byte {
+	buff = strconv.AppendUint(buff, uint64(f.PayloadType), 10)
+	buff = append(buff, ' ')
+
+	return appendFormatParams(buff, f.Params)
+}
+
+func appendFormatParams(buff []byte, params []FormatParam) []byte {
+	for i, param := range params {
+		if i > 0 {
+			buff = append(buff, ';')
+		}
+
+		if len(param.Key) > 0 {
+			buff = append(buff, param.Key...)
+			buff = append(buff, '=')
+		}
+
+		buff = append(buff, param.Value...)
+	}
+
+	return buff
+}
+
+// Codec is a media format with its rtpmap and fmtp attributes joined together
+type Codec struct {
+	PayloadType uint8
+	Name        string
+	ClockRate   int
+	// Channels is zero if not specified, which means a single channel for audio
+	Channels int
+	Params   []FormatParam
+}
+
+// Matches reports whether both codecs describe the same encoding. Payload types and
+// format parameters aren't compared
+func (c Codec) Matches(other Codec) bool {
+	channels, otherChannels := c.Channels, other.Channels
+	if channels == 0 {
+		channels = 1
+	}
+
+	if otherChannels == 0 {
+		otherChannels = 1
+	}
+
+	return strings.EqualFold(c.Name, other.Name) &&
+		c.ClockRate == other.ClockRate &&
+		channels == otherChannels
+}
+
+// Param returns the value of the format parameter. Keys are case-insensitive
+func (c Codec) Param(key string) (value string, found bool) {
+	return FormatParams{Params: c.Params}.Get(key)
+}
+
+// staticPayloadTypes are the RTP/AVP payload types, whose rtpmap may be omitted.
+// See RFC 3551 6
+var staticPayloadTypes = map[uint8]RTPMap{
+	0:  {Encoding: "PCMU", ClockRate: 8000},
+	3:  {Encoding: "GSM", ClockRate: 8000},
+	4:  {Encoding: "G723", ClockRate: 8000},
+	5:  {Encoding: "DVI4", ClockRate: 8000},
+	6:  {Encoding: "DVI4", ClockRate: 16000},
+	7:  {Encoding: "LPC", ClockRate: 8000},
+	8:  {Encoding: "PCMA", ClockRate: 8000},
+	9:  {Encoding: "G722", ClockRate: 8000},
+	10: {Encoding: "L16", ClockRate: 44100, Channels: 2},
+	11: {Encoding: "L16", ClockRate: 44100},
+	12: {Encoding: "QCELP", ClockRate: 8000},
+	13: {Encoding: "CN", ClockRate: 8000},
+	14: {Encoding: "MPA", ClockRate: 90000},
+	15: {Encoding: "G728", ClockRate: 8000},
+	16: {Encoding: "DVI4", ClockRate: 11025},
+	17: {Encoding: "DVI4", ClockRate: 22050},
+	18: {Encoding: "G729", ClockRate: 8000},
+	25: {Encoding: "CelB", ClockRate: 90000},
+	26: {Encoding: "JPEG", ClockRate: 90000},
+	28: {Encoding: "nv", ClockRate: 90000},
+	31: {Encoding: "H261", ClockRate: 90000},
+	32: {Encoding: "MPV", ClockRate: 90000},
+	33: {Encoding: "MP2T", ClockRate: 90000},
+	34: {Encoding: "H263", ClockRate: 90000},
+}
+
+// Codecs returns the RTP formats of the media in the order of preference. Static
+// payload types without the rtpmap attribute are resolved from RFC 3551. Non-RTP
+// media has no codecs, so nil is returned
+func (m Media) Codecs() ([]Codec, error) {
+	if !m.Proto.RTP() {
+		return nil, nil
+	}
+
+	codecs := make([]Codec, len(m.Formats))
+
+	for i, format := range m.Formats {
+		pt, err := parsePayloadType(format)
+		if err != nil {
+			return nil, err
+		}
+
+		codecs[i].PayloadType = pt
+	}
+
+	for _, attr := range m.Attributes {
+		switch attr.Key {
+		case "rtpmap":
+			rtpmap, err := RTPMap{}.Parse(attr.Value)
+			if err != nil {
+				return nil, err
+			}
+
+			if codec := findCodec(codecs, rtpmap.PayloadType); codec != nil {
+				codec.Name, codec.ClockRate, codec.Channels = rtpmap.Encoding, rtpmap.ClockRate, rtpmap.Channels
+			}
+		case "fmtp":
+			fmtp, err := FormatParams{}.Parse(attr.Value)
+			if err != nil {
+				return nil, err
+			}
+
+			if codec := findCodec(codecs, fmtp.PayloadType); codec != nil {
+				codec.Params = fmtp.Params
+			}
+		}
+	}
+
+	for i := range codecs {
+		if len(codecs[i].Name) > 0 {
+			continue
+		}
+
+		static, found := staticPayloadTypes[codecs[i].PayloadType]
+		if !found {
+			// dynamic payload types must always be described by the rtpmap
+			return nil, ErrUnknownPayloadType
+		}
+
+		codecs[i].Name, codecs[i].ClockRate, codecs[i].Channels = static.Encoding, static.ClockRate, static.Channels
+	}
+
+	return codecs, nil
+}
+
+// SetCodecs replaces the formats of the media together with their rtpmap and fmtp
+// attributes. The new attributes take the place of the first replaced one, other
+// attributes are left untouched
+func (m *Media) SetCodecs(codecs []Codec) {
+	codecAttrs := make([]Attribute, 0, len(codecs)*2)
+	m.Formats = make([]string, len(codecs))
+
+	for i, codec := range codecs {
+		m.Formats[i] = strconv.Itoa(int(codec.PayloadType))
+		rtpmap := RTPMap{
+			PayloadType: codec.PayloadType,
+			Encoding:    codec.Name,
+			ClockRate:   codec.ClockRate,
+			Channels:    codec.Channels,
+		}
+		codecAttrs = append(codecAttrs, Attribute{Key: "rtpmap", Value: string(rtpmap.AppendTo(nil))})
+
+		if len(codec.Params) > 0 {
+			fmtp := FormatParams{PayloadType: codec.PayloadType, Params: codec.Params}
+			codecAttrs = append(codecAttrs, Attribute{Key: "fmtp", Value: string(fmtp.AppendTo(nil))})
+		}
+	}
+
+	attrs := make([]Attribute, 0, len(m.Attributes)+len(codecAttrs))
+	inserted := false
+
+	for _, attr := range m.Attributes {
+		if attr.Key != "rtpmap" && attr.Key != "fmtp" {
+			attrs = append(attrs, attr)
+			continue
+		}
+
+		if !inserted {
+			attrs = append(attrs, codecAttrs...)
+			inserted = true
+		}
+	}
+
+	if !inserted {
+		attrs = append(attrs, codecAttrs...)
+	}
+
+	m.Attributes = attrs
+}
+
+// Ptime returns the packet time in milliseconds, if specified
+func (m Media) Ptime() (ms float64, found bool) {
+	return m.floatAttribute("ptime")
+}
+
+// MaxPtime returns the maximal packet time in milliseconds, if specified
+func (m Media) MaxPtime() (ms float64, found bool) {
+	return m.floatAttribute("maxptime")
+}
+
+func (m Media) floatAttribute(key string) (float64, bool) {
+	value, found := m.Attribute(key)
+	if !found {
+		return 0, false
+	}
+
+	// ptime is allowed to be fractional, e.g. 2.5
+	ms, err := strconv.ParseFloat(value, 64)
+	if err != nil || ms <= 0 {
+		return 0, false
+	}
+
+	return ms, true
+}
+
+func findCodec(codecs []Codec, pt uint8) *Codec {
+	for i := range codecs {
+		if codecs[i].PayloadType == pt {
+			return &codecs[i]
+		}
+	}
+
+	return nil
+}
+
+func parsePayloadType(value string) (uint8, error) {
+	pt, err := strconv.ParseUint(value, 10, 8)
+	if err != nil || pt > 127 {
+		return 0, ErrBadSyntax
+	}
+
+	return uint8(pt), nil
+}
@@ -0,0 +1,99 @@
+package sdp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodecs(t *testing.T) {
+	t.Run("rtpmap, fmtp and static payload types", func(t *testing.T) {
+		sample := []byte("v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=-\r\n" +
+			"t=0 0\r\n" +
+			"m=audio 49170 RTP/AVP 111 0 18 101\r\n" +
+			"a=rtpmap:111 opus/48000/2\r\n" +
+			"a=fmtp:111 minptime=10; useinbandfec=1\r\n" +
+			"a=rtpmap:101 telephone-event/8000\r\n" +
+			"a=fmtp:101 0-15\r\n" +
+			"a=fmtp:18 annexb=no\r\n" +
+			"a=ptime:20\r\n" +
+			"a=maxptime:2.5\r\n")
+		desc, err := NewParser().Parse(sample)
+		require.NoError(t, err)
+
+		media := desc.Media[0]
+		codecs, err := media.Codecs()
+		require.NoError(t, err)
+		require.Equal(t, []Codec{
+			{
+				PayloadType: 111, Name: "opus", ClockRate: 48000, Channels: 2,
+				Params: []FormatParam{{Key: "minptime", Value: "10"}, {Key: "useinbandfec", Value: "1"}},
+			},
+			{PayloadType: 0, Name: "PCMU", ClockRate: 8000},
+			{PayloadType: 18, Name: "G729", ClockRate: 8000, Params: []FormatParam{{Key: "annexb", Value: "no"}}},
+			{PayloadType: 101, Name: "telephone-event", ClockRate: 8000, Params: []FormatParam{{Value: "0-15"}}},
+		}, codecs)
+
+		value, found := codecs[0].Param("UseInbandFEC")
+		require.True(t, found)
+		require.Equal(t, "1", value)
+
+		ptime, found := media.Ptime()
+		require.True(t, found)
+		require.Equal(t, 20.0, ptime)
+		maxptime, found := media.MaxPtime()
+		require.True(t, found)
+		require.Equal(t, 2.5, maxptime)
+	})
+
+	t.Run("dynamic payload type without rtpmap", func(t *testing.T) {
+		media := Media{Type: Audio, Proto: RTPAVP, Formats: []string{"0", "96"}}
+		_, err := media.Codecs()
+		require.Equal(t, ErrUnknownPayloadType, err)
+	})
+
+	t.Run("non-rtp media", func(t *testing.T) {
+		media := Media{Type: Image, Proto: UDPTL, Formats: []string{"t38"}}
+		codecs, err := media.Codecs()
+		require.NoError(t, err)
+		require.Nil(t, codecs)
+	})
+
+	t.Run("set codecs", func(t *testing.T) {
+		media := Media{
+			Type:    Audio,
+			Proto:   RTPAVP,
+			Formats: []string{"0", "8", "96"},
+			Attributes: []Attribute{
+				{Key: "rtpmap", Value: "96 iLBC/8000"},
+				{Key: "fmtp", Value: "96 mode=30"},
+				{Key: "sendrecv"},
+			},
+		}
+		media.SetCodecs([]Codec{
+			{PayloadType: 8, Name: "PCMA", ClockRate: 8000},
+			{PayloadType: 101, Name: "telephone-event", ClockRate: 8000, Params: []FormatParam{{Value: "0-16"}}},
+		})
+		require.Equal(t, []string{"8", "101"}, media.Formats)
+		require.Equal(t, []Attribute{
+			{Key: "rtpmap", Value: "8 PCMA/8000"},
+			{Key: "rtpmap", Value: "101 telephone-event/8000"},
+			{Key: "fmtp", Value: "101 0-16"},
+			{Key: "sendrecv"},
+		}, media.Attributes)
+	})
+
+	t.Run("matches", func(t *testing.T) {
+		require.True(t, Codec{Name: "PCMU", ClockRate: 8000}.Matches(Codec{Name: "pcmu", ClockRate: 8000, Channels: 1}))
+		require.False(t, Codec{Name: "opus", ClockRate: 48000, Channels: 2}.Matches(Codec{Name: "opus", ClockRate: 48000}))
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		for _, sample := range []string{"96", "96 opus", "96 opus/", "256 opus/48000", "96 opus/48000/0"} {
+			_, err := RTPMap{}.Parse(sample)
+			require.Equalf(t, ErrBadSyntax, err, "sample: %q", sample)
+		}
+	})
+}
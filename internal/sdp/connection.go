@@ -0,0 +1,156 @@
+package sdp
+
+import (
+	"net"
+	"strings"
+)
+
+// Endpoint is the concrete address and port a media stream is sent to
+type Endpoint struct {
+	Address string
+	Port    int
+}
+
+// Endpoints resolves the addresses and ports the i-th media uses. The media-level
+// connection info takes precedence over the session-level one. Multicast address
+// ranges are expanded into consecutive addresses, and multiple ports are paired with
+// them one-to-one, as RFC 8866 5.14 defines. For RTP, each stream takes a pair of
+// ports (RTP and RTCP), so ports go in steps of two.
+//
+// Addresses are validated against their type. Fully qualified domain names are
+// accepted for unicast addresses only.
+func (d Description) Endpoints(i int) ([]Endpoint, error) {
+	media := d.Media[i]
+	if media.Port == 0 {
+		return nil, ErrNoTransport
+	}
+
+	infos := d.connectionInfo(media)
+	if len(infos) == 0 {
+		return nil, ErrMissingField
+	}
+
+	var addrs []string
+
+	for _, info := range infos {
+		expanded, err := expandAddress(info)
+		if err != nil {
+			return nil, err
+		}
+
+		addrs = append(addrs, expanded...)
+	}
+
+	ports := media.PortCount
+	if ports == 0 {
+		ports = 1
+	}
+
+	step := 1
+	if media.Proto.RTP() {
+		step = 2
+	}
+
+	count := ports
+	if len(addrs) > count {
+		count = len(addrs)
+	}
+
+	if len(addrs) != 1 && ports != 1 && len(addrs) != ports {
+		// neither a single address nor a single port, and they can't be paired
+		return nil, ErrBadSyntax
+	}
+
+	endpoints := make([]Endpoint, count)
+	for n := range endpoints {
+		endpoint := Endpoint{Address: addrs[0], Port: media.Port}
+		if len(addrs) > 1 {
+			endpoint.Address = addrs[n]
+		}
+
+		if ports > 1 {
+			endpoint.Port += n * step
+		}
+
+		if endpoint.Port > 65535 {
+			return nil, ErrBadSyntax
+		}
+
+		endpoints[n] = endpoint
+	}
+
+	return endpoints, nil
+}
+
+// expandAddress validates the connection address and expands its range
+func expandAddress(info ConnectionInfo) ([]string, error) {
+	ip := net.ParseIP(info.Address)
+	if ip == nil {
+		// must be FQDN then, which is meaningless with TTL or range
+		if info.TTL > 0 || info.AddrRange > 0 || !validHostname(info.Address) {
+			return nil, ErrBadAddress
+		}
+
+		return []string{info.Address}, nil
+	}
+
+	isIP4 := ip.To4() != nil && !strings.Contains(info.Address, ":")
+	if isIP4 != (info.AddrType == IP4) {
+		return nil, ErrBadAddress
+	}
+
+	if isIP4 && ip.IsMulticast() != (info.TTL > 0) {
+		// TTL is compulsory for IP4 multicast and forbidden otherwise. See RFC 8866 5.7
+		return nil, ErrBadAddress
+	}
+
+	if info.AddrRange <= 1 {
+		return []string{info.Address}, nil
+	}
+
+	if !ip.IsMulticast() {
+		return nil, ErrBadAddress
+	}
+
+	if isIP4 {
+		ip = ip.To4()
+	}
+
+	addrs := make([]string, info.AddrRange)
+	for n := range addrs {
+		addrs[n] = ip.String()
+		ip = nextIP(ip)
+	}
+
+	return addrs, nil
+}
+
+func nextIP(ip net.IP) net.IP {
+	next := make(net.IP, len(ip))
+	copy(next, ip)
+
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			break
+		}
+	}
+
+	return next
+}
+
+func validHostname(host string) bool {
+	if len(host) == 0 || len(host) > 253 {
+		return false
+	}
+
+	for i := 0; i < len(host); i++ {
+		switch c := host[i]; {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '.':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,102 @@
+package sdp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpoints(t *testing.T) {
+	desc, err := NewParser().Parse([]byte("v=0\r\n" +
+		"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+		"s=-\r\n" +
+		"c=IN IP4 224.2.1.1/127/3\r\n" +
+		"t=0 0\r\n" +
+		"m=audio 49170 RTP/AVP 0\r\n" +
+		"m=video 49170/3 RTP/AVP 31\r\n" +
+		"m=audio 49180/2 RTP/AVP 0\r\n" +
+		"c=IN IP4 192.0.2.1\r\n" +
+		"m=audio 5000 udp 0\r\n" +
+		"c=IN IP6 FF15::101/3\r\n" +
+		"m=audio 5000 RTP/AVP 0\r\n" +
+		"c=IN IP4 media.example.com\r\n" +
+		"m=audio 0 RTP/AVP 0\r\n" +
+		"m=audio 49170/2 RTP/AVP 0\r\n"))
+	require.NoError(t, err)
+
+	for i, want := range [][]Endpoint{
+		{{"224.2.1.1", 49170}, {"224.2.1.2", 49170}, {"224.2.1.3", 49170}},
+		{{"224.2.1.1", 49170}, {"224.2.1.2", 49172}, {"224.2.1.3", 49174}},
+		{{"192.0.2.1", 49180}, {"192.0.2.1", 49182}},
+		{{"ff15::101", 5000}, {"ff15::102", 5000}, {"ff15::103", 5000}},
+		{{"media.example.com", 5000}},
+	} {
+		endpoints, err := desc.Endpoints(i)
+		require.NoError(t, err, i)
+		require.Equal(t, want, endpoints, i)
+	}
+
+	_, err = desc.Endpoints(5)
+	require.Equal(t, ErrNoTransport, err)
+	_, err = desc.Endpoints(6)
+	require.Equal(t, ErrBadSyntax, err, "3 addresses can't be paired with 2 ports")
+
+	t.Run("validation", func(t *testing.T) {
+		for _, info := range []ConnectionInfo{
+			{NetType: IN, AddrType: IP4, Address: "2001:db8::1", TTL: -1},
+			{NetType: IN, AddrType: IP6, Address: "192.0.2.1", TTL: -1},
+			{NetType: IN, AddrType: IP4, Address: "224.2.1.1", TTL: -1},
+			{NetType: IN, AddrType: IP4, Address: "192.0.2.1", TTL: 127},
+			{NetType: IN, AddrType: IP4, Address: "192.0.2.1", TTL: -1, AddrRange: 2},
+			{NetType: IN, AddrType: IP4, Address: "bad_host", TTL: -1},
+		} {
+			_, err := expandAddress(info)
+			require.Equal(t, ErrBadAddress, err, info)
+		}
+
+		for _, addr := range []string{"FF15::101/3/1", "FF15::101/0", "224.2.1.1/256", "224.2.1.1/127/0"} {
+			typ := IP4
+			if addr[0] == 'F' {
+				typ = IP6
+			}
+
+			_, _, _, err := parseAddress(addr, typ)
+			require.Equal(t, ErrBadSyntax, err, addr)
+		}
+	})
+
+	t.Run("IP6 range over 255", func(t *testing.T) {
+		info, err := ConnectionInfo{}.Parse("IN IP6 ff15::101/300")
+		require.NoError(t, err)
+		require.Equal(t, 300, info.AddrRange)
+
+		addrs, err := expandAddress(info)
+		require.NoError(t, err)
+		require.Len(t, addrs, 300)
+		require.Equal(t, "ff15::22c", addrs[299])
+	})
+
+	t.Run("after SetConnection", func(t *testing.T) {
+		desc := desc
+		desc.SetConnection(2, ConnectionInfo{NetType: IN, AddrType: IP4, Address: "203.0.113.5"}, 6000)
+		desc.SetConnection(3, ConnectionInfo{NetType: IN, AddrType: IP4, Address: "232.0.0.1", TTL: 16}, 6002)
+
+		endpoints, err := desc.Endpoints(2)
+		require.NoError(t, err)
+		require.Equal(t, []Endpoint{{"203.0.113.5", 6000}, {"203.0.113.5", 6002}}, endpoints)
+
+		endpoints, err = desc.Endpoints(3)
+		require.NoError(t, err)
+		require.Equal(t, []Endpoint{{"232.0.0.1", 6002}}, endpoints)
+
+		// what's marshaled is what's resolved
+		reparsed, err := NewParser().Parse(desc.Marshal())
+		require.NoError(t, err)
+		for _, i := range []int{2, 3} {
+			endpoints, err := reparsed.Endpoints(i)
+			require.NoError(t, err)
+			expected, _ := desc.Endpoints(i)
+			require.Equal(t, expected, endpoints)
+		}
+	})
+}
@@ -1,3 +1,10 @@
+// Package sdp implements the Session Description Protocol (RFC 8866) with its
+// offer/answer and WebRTC extensions.
+//
+// Descriptions are often copied by value and kept around, e.g. the remote one kept by
+// a B2BUA while the forwarded copy is rewritten. So the setters never write into the
+// shared slices (media, attributes, formats, etc.): they're allocated anew before
+// being modified, and the other copies stay intact
 package sdp
 
 type Description struct {
@@ -6,28 +13,31 @@ type Description struct {
 }
 
 type Session struct {
-	Protocol       string           // compulsory
-	Originator     Origin           // compulsory
-	Name           string           // compulsory
-	Info           string           // optional
-	URI            string           // optional
-	Email          string           // optional
-	Phone          string           // optional
-	ConnectionInfo []ConnectionInfo // optional
-	BandwidthInfo  []Bandwidth      // optional
-
-	// TODO: add time descriptions here
-
-	TimeZoneAdjustments []string      // optional
-	EncryptionKey       EncryptionKey // optional
-	Attributes          []Attribute   // optional
+	Protocol       string            // compulsory
+	Originator     Origin            // compulsory
+	Name           string            // compulsory
+	Info           string            // optional
+	URI            string            // optional
+	Email          string            // optional
+	Phone          string            // optional
+	ConnectionInfo []ConnectionInfo  // optional
+	BandwidthInfo  []Bandwidth       // optional
+	Times          []TimeDescription // compulsory
+	EncryptionKey  EncryptionKey     // optional
+	Attributes     []Attribute       // optional
 }
 
 type Media struct {
-	Name           string           // compulsory
+	Type MediaType // compulsory
+	Port int       // compulsory
+	// PortCount is the number of ports after the slash. Zero means it was omitted,
+	// which is equivalent to a single port
+	PortCount      int              // optional
+	Proto          TransportProto   // compulsory
+	Formats        []string         // compulsory
 	Title          string           // optional
 	ConnectionInfo []ConnectionInfo // optional if included in session-level
-	BandwidthInfo  []string         // optional
-	EncryptionKey  string           // optional
-	Attributes     []string         // optional
+	BandwidthInfo  []Bandwidth      // optional
+	EncryptionKey  EncryptionKey    // optional
+	Attributes     []Attribute      // optional
 }
@@ -0,0 +1,137 @@
+package sdp
+
+// Direction is the media direction attribute. See RFC 8866 6.7
+type Direction string
+
+const (
+	SendRecv Direction = "sendrecv"
+	SendOnly Direction = "sendonly"
+	RecvOnly Direction = "recvonly"
+	Inactive Direction = "inactive"
+)
+
+// legacyHoldAddress is the connection address RFC 2543 used to put a stream on hold
+const legacyHoldAddress = "0.0.0.0"
+
+// Valid reports whether the direction is one of the known ones
+func (d Direction) Valid() bool {
+	switch d {
+	case SendRecv, SendOnly, RecvOnly, Inactive:
+		return true
+	default:
+		return false
+	}
+}
+
+// Sends reports whether the media is sent in the direction
+func (d Direction) Sends() bool {
+	return d == SendRecv || d == SendOnly
+}
+
+// Receives reports whether the media is received in the direction
+func (d Direction) Receives() bool {
+	return d == SendRecv || d == RecvOnly
+}
+
+// Reverse returns the direction as it's seen by the other party
+func (d Direction) Reverse() Direction {
+	switch d {
+	case SendOnly:
+		return RecvOnly
+	case RecvOnly:
+		return SendOnly
+	default:
+		return d
+	}
+}
+
+// Attribute returns the property attribute representing the direction
+func (d Direction) Attribute() Attribute {
+	return Attribute{Key: string(d)}
+}
+
+// Direction returns the direction explicitly set at the media level
+func (m Media) Direction() (Direction, bool) {
+	return findDirection(m.Attributes)
+}
+
+// SetDirection replaces the media-level direction attribute
+func (m *Media) SetDirection(dir Direction) {
+	m.Attributes = withDirection(m.Attributes, dir)
+}
+
+// Direction returns the direction explicitly set at the session level
+func (s Session) Direction() (Direction, bool) {
+	return findDirection(s.Attributes)
+}
+
+// SetDirection replaces the session-level direction attribute
+func (s *Session) SetDirection(dir Direction) {
+	s.Attributes = withDirection(s.Attributes, dir)
+}
+
+// Direction returns the effective direction of the i-th media. The media-level
+// attribute takes precedence over the session-level one, and sendrecv is the default.
+//
+// The legacy hold of RFC 2543, that is the 0.0.0.0 connection address, means the
+// stream must not be sent to the party, so the receiving part is removed from
+// the direction.
+func (d Description) Direction(i int) Direction {
+	media := d.Media[i]
+
+	dir, found := media.Direction()
+	if !found {
+		if dir, found = d.Session.Direction(); !found {
+			dir = SendRecv
+		}
+	}
+
+	if d.legacyHold(media) {
+		switch dir {
+		case SendRecv:
+			dir = SendOnly
+		case RecvOnly:
+			dir = Inactive
+		}
+	}
+
+	return dir
+}
+
+// Held reports whether the i-th media is put on hold by the party, i.e. the party
+// doesn't want to receive it. Both a=sendonly (or a=inactive) and the legacy
+// c=0.0.0.0 forms are recognized. Rejected streams aren't considered held
+func (d Description) Held(i int) bool {
+	return d.Media[i].Port != 0 && !d.Direction(i).Receives()
+}
+
+func (d Description) legacyHold(media Media) bool {
+	for _, info := range d.connectionInfo(media) {
+		if info.AddrType == IP4 && info.Address == legacyHoldAddress {
+			return true
+		}
+	}
+
+	return false
+}
+
+func findDirection(attrs []Attribute) (Direction, bool) {
+	for _, attr := range attrs {
+		if dir := Direction(attr.Key); dir.Valid() && len(attr.Value) == 0 {
+			return dir, true
+		}
+	}
+
+	return "", false
+}
+
+func withDirection(attrs []Attribute, dir Direction) []Attribute {
+	result := make([]Attribute, 0, len(attrs)+1)
+	for _, attr := range attrs {
+		if !Direction(attr.Key).Valid() {
+			result = append(result, attr)
+		}
+	}
+
+	return append(result, dir.Attribute())
+}
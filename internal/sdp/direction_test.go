@@ -0,0 +1,71 @@
+package sdp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirection(t *testing.T) {
+	t.Run("inheritance", func(t *testing.T) {
+		desc, err := NewParser().Parse([]byte("v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=-\r\n" +
+			"c=IN IP4 192.0.2.1\r\n" +
+			"t=0 0\r\n" +
+			"a=recvonly\r\n" +
+			"m=audio 49170 RTP/AVP 0\r\n" +
+			"m=video 51372 RTP/AVP 31\r\n" +
+			"a=inactive\r\n" +
+			"m=text 0 RTP/AVP 98\r\n" +
+			"a=sendonly\r\n"))
+		require.NoError(t, err)
+
+		require.Equal(t, RecvOnly, desc.Direction(0))
+		require.Equal(t, Inactive, desc.Direction(1))
+		require.Equal(t, SendOnly, desc.Direction(2))
+		require.False(t, desc.Held(0))
+		require.True(t, desc.Held(1))
+		require.False(t, desc.Held(2), "rejected streams aren't held")
+
+		desc.Session.Attributes = nil
+		require.Equal(t, SendRecv, desc.Direction(0))
+	})
+
+	t.Run("legacy hold", func(t *testing.T) {
+		desc, err := NewParser().Parse([]byte("v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=-\r\n" +
+			"c=IN IP4 0.0.0.0\r\n" +
+			"t=0 0\r\n" +
+			"m=audio 49170 RTP/AVP 0\r\n" +
+			"m=audio 49172 RTP/AVP 0\r\n" +
+			"a=recvonly\r\n" +
+			"m=audio 49174 RTP/AVP 0\r\n" +
+			"c=IN IP4 192.0.2.1\r\n"))
+		require.NoError(t, err)
+
+		require.Equal(t, SendOnly, desc.Direction(0))
+		require.True(t, desc.Held(0))
+		require.Equal(t, Inactive, desc.Direction(1))
+		require.Equal(t, SendRecv, desc.Direction(2), "media-level c= overrides the session one")
+		require.False(t, desc.Held(2))
+	})
+
+	t.Run("set direction", func(t *testing.T) {
+		attrs := []Attribute{{Key: "rtpmap", Value: "0 PCMU/8000"}, {Key: "sendrecv"}, {Key: "ptime", Value: "20"}}
+		media := Media{Attributes: attrs}
+		media.SetDirection(SendOnly)
+
+		dir, found := media.Direction()
+		require.True(t, found)
+		require.Equal(t, SendOnly, dir)
+		require.Equal(t, []Attribute{
+			{Key: "rtpmap", Value: "0 PCMU/8000"}, {Key: "ptime", Value: "20"}, {Key: "sendonly"},
+		}, media.Attributes)
+		require.Equal(t, Attribute{Key: "sendrecv"}, attrs[1], "shared attributes must be kept intact")
+		require.Equal(t, RecvOnly, dir.Reverse())
+		require.True(t, dir.Sends())
+		require.False(t, dir.Receives())
+	})
+}
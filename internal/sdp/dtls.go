@@ -0,0 +1,263 @@
+package sdp
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+
+	// hash functions the fingerprints may be computed with
+	_ "crypto/md5"
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+)
+
+var hashFunctions = map[string]crypto.Hash{
+	"md5":     crypto.MD5,
+	"sha-1":   crypto.SHA1,
+	"sha-224": crypto.SHA224,
+	"sha-256": crypto.SHA256,
+	"sha-384": crypto.SHA384,
+	"sha-512": crypto.SHA512,
+}
+
+// Fingerprint is the value of the fingerprint attribute, following the grammar:
+//
+//	fingerprint-attribute = "fingerprint" ":" hash-func SP fingerprint
+//	fingerprint = 2UHEX *(":" 2UHEX)
+//
+// See RFC 8122 5.
+type Fingerprint struct {
+	// Hash is the name of the hash function in lower case, e.g. sha-256
+	Hash  string
+	Value []byte
+}
+
+func (f Fingerprint) Parse(value string) (Fingerprint, error) {
+	sp := strings.IndexByte(value, ' ')
+	if sp <= 0 {
+		return f, ErrBadSyntax
+	}
+
+	f.Hash = strings.ToLower(value[:sp])
+	hash, known := hashFunctions[f.Hash]
+	if !known {
+		return f, ErrUnknownHashFunction
+	}
+
+	value = strings.TrimLeft(value[sp+1:], " ")
+	if (len(value)+1)%3 != 0 {
+		return f, ErrBadSyntax
+	}
+
+	f.Value = make([]byte, (len(value)+1)/3)
+	for i := range f.Value {
+		if i > 0 && value[i*3-1] != ':' {
+			return f, ErrBadSyntax
+		}
+
+		if _, err := hex.Decode(f.Value[i:i+1], []byte(value[i*3:i*3+2])); err != nil {
+			return f, ErrBadSyntax
+		}
+	}
+
+	if len(f.Value) != hash.Size() {
+		return f, ErrBadSyntax
+	}
+
+	return f, nil
+}
+
+func (f Fingerprint) AppendTo(buff []byte) []byte {
+	const hexUpper = "0123456789ABCDEF"
+
+	buff = append(buff, f.Hash...)
+	buff = append(buff, ' ')
+
+	for i, b := range f.Value {
+		if i > 0 {
+			buff = append(buff, ':')
+		}
+
+		buff = append(buff, hexUpper[b>>4], hexUpper[b&0xf])
+	}
+
+	return buff
+}
+
+// NewFingerprint computes the fingerprint of the certificate with the hash function,
+// e.g. sha-256
+func NewFingerprint(hash string, cert *x509.Certificate) (Fingerprint, error) {
+	fn, known := hashFunctions[strings.ToLower(hash)]
+	if !known {
+		return Fingerprint{}, ErrUnknownHashFunction
+	}
+
+	h := fn.New()
+	h.Write(cert.Raw)
+
+	return Fingerprint{Hash: strings.ToLower(hash), Value: h.Sum(nil)}, nil
+}
+
+// Matches reports whether the fingerprint belongs to the certificate
+func (f Fingerprint) Matches(cert *x509.Certificate) bool {
+	actual, err := NewFingerprint(f.Hash, cert)
+	if err != nil {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare(actual.Value, f.Value) == 1
+}
+
+// VerifyCertificate checks the certificate presented by the peer in the DTLS handshake
+// against the advertised fingerprints. A single match is enough, as the peer may
+// advertise the fingerprints computed with different hash functions
+func VerifyCertificate(fingerprints []Fingerprint, cert *x509.Certificate) error {
+	for _, fingerprint := range fingerprints {
+		if fingerprint.Matches(cert) {
+			return nil
+		}
+	}
+
+	return ErrFingerprintMismatch
+}
+
+// Fingerprints returns the fingerprints of the i-th media. Media-level attributes
+// take precedence over the session-level ones. See RFC 8122 5
+func (d Description) Fingerprints(i int) ([]Fingerprint, error) {
+	fingerprints, err := parseFingerprints(d.Media[i].Attributes)
+	if err != nil || len(fingerprints) > 0 {
+		return fingerprints, err
+	}
+
+	return parseFingerprints(d.Session.Attributes)
+}
+
+func parseFingerprints(attrs []Attribute) (fingerprints []Fingerprint, err error) {
+	for _, attr := range attrs {
+		if attr.Key != "fingerprint" {
+			continue
+		}
+
+		fingerprint, err := Fingerprint{}.Parse(attr.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		fingerprints = append(fingerprints, fingerprint)
+	}
+
+	return fingerprints, nil
+}
+
+// Setup is the value of the setup attribute, which determines the DTLS role. See
+// RFC 4145 4 and RFC 8842 5
+type Setup string
+
+const (
+	// Active is the DTLS client
+	Active Setup = "active"
+	// Passive is the DTLS server
+	Passive  Setup = "passive"
+	ActPass  Setup = "actpass"
+	HoldConn Setup = "holdconn"
+)
+
+func (s Setup) Parse(value string) (Setup, error) {
+	switch setup := Setup(value); setup {
+	case Active, Passive, ActPass, HoldConn:
+		return setup, nil
+	default:
+		return s, ErrBadSyntax
+	}
+}
+
+// Answer returns the setup role the answerer takes for the offered one. When the
+// offerer leaves the choice, the answerer becomes active, as RFC 8842 5.3 recommends
+func (s Setup) Answer() Setup {
+	switch s {
+	case Active:
+		return Passive
+	case Passive, ActPass:
+		return Active
+	default:
+		return s
+	}
+}
+
+// AcceptsAnswer reports whether the answered setup role is permitted for the
+// offered one
+func (s Setup) AcceptsAnswer(answered Setup) bool {
+	switch s {
+	case Active:
+		return answered == Passive
+	case Passive:
+		return answered == Active
+	case ActPass:
+		return answered == Active || answered == Passive
+	case HoldConn:
+		return answered == HoldConn
+	default:
+		return false
+	}
+}
+
+// Setup returns the setup role of the i-th media. Media-level attribute takes
+// precedence over the session-level one. Empty value is returned, if neither
+// is present
+func (d Description) Setup(i int) (Setup, error) {
+	value, found := d.Media[i].Attribute("setup")
+	if !found {
+		if value, found = d.Session.Attribute("setup"); !found {
+			return "", nil
+		}
+	}
+
+	return Setup("").Parse(value)
+}
+
+// TLSID is the value of the tls-id attribute, identifying the DTLS association. See
+// RFC 8842 4
+type TLSID string
+
+func (t TLSID) Parse(value string) (TLSID, error) {
+	if len(value) < 20 || len(value) > 255 {
+		return t, ErrBadSyntax
+	}
+
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9',
+			c == '+', c == '/', c == '-', c == '_':
+		default:
+			return t, ErrBadSyntax
+		}
+	}
+
+	return TLSID(value), nil
+}
+
+// NewTLSID generates a random identifier. A new one must be generated each time a
+// new DTLS association is to be established
+func NewTLSID() (TLSID, error) {
+	var random [24]byte
+	if _, err := rand.Read(random[:]); err != nil {
+		return "", err
+	}
+
+	return TLSID(base64.RawURLEncoding.EncodeToString(random[:])), nil
+}
+
+// TLSID returns the tls-id of the media. Empty value is returned, if it's absent
+func (m Media) TLSID() (TLSID, error) {
+	value, found := m.Attribute("tls-id")
+	if !found {
+		return "", nil
+	}
+
+	return TLSID("").Parse(value)
+}
@@ -0,0 +1,117 @@
+package sdp
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newCertificate(t *testing.T) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "WebRTC"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return cert
+}
+
+func TestDTLS(t *testing.T) {
+	t.Run("fingerprint", func(t *testing.T) {
+		const value = "sha-256 " +
+			"4A:AD:B9:B1:3F:82:18:3B:54:02:12:DF:3E:5D:49:6B:19:E5:7C:AB:3E:4B:65:CF:6F:B4:C3:7E:6A:72:F0:0B"
+		fingerprint, err := Fingerprint{}.Parse(value)
+		require.NoError(t, err)
+		require.Equal(t, "sha-256", fingerprint.Hash)
+		require.Len(t, fingerprint.Value, 32)
+		require.Equal(t, value, string(fingerprint.AppendTo(nil)))
+
+		_, err = Fingerprint{}.Parse("sha-3 4A:AD")
+		require.Equal(t, ErrUnknownHashFunction, err)
+		_, err = Fingerprint{}.Parse("sha-1 4A:AD")
+		require.Equal(t, ErrBadSyntax, err)
+		_, err = Fingerprint{}.Parse("sha-1 4A-AD")
+		require.Equal(t, ErrBadSyntax, err)
+	})
+
+	t.Run("certificate", func(t *testing.T) {
+		cert, other := newCertificate(t), newCertificate(t)
+		fingerprint, err := NewFingerprint("SHA-256", cert)
+		require.NoError(t, err)
+
+		parsed, err := Fingerprint{}.Parse(string(fingerprint.AppendTo(nil)))
+		require.NoError(t, err)
+		require.True(t, parsed.Matches(cert))
+		require.False(t, parsed.Matches(other))
+
+		sha1, err := NewFingerprint("sha-1", other)
+		require.NoError(t, err)
+		require.NoError(t, VerifyCertificate([]Fingerprint{parsed, sha1}, other))
+		require.Equal(t, ErrFingerprintMismatch, VerifyCertificate([]Fingerprint{sha1}, cert))
+	})
+
+	t.Run("setup negotiation", func(t *testing.T) {
+		require.Equal(t, Active, ActPass.Answer())
+		require.Equal(t, Passive, Active.Answer())
+		require.Equal(t, Active, Passive.Answer())
+		require.True(t, ActPass.AcceptsAnswer(Passive))
+		require.False(t, ActPass.AcceptsAnswer(ActPass))
+		require.False(t, Active.AcceptsAnswer(Active))
+
+		_, err := Setup("").Parse("both")
+		require.Equal(t, ErrBadSyntax, err)
+	})
+
+	t.Run("attributes", func(t *testing.T) {
+		desc, err := NewParser().Parse([]byte("v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=-\r\n" +
+			"t=0 0\r\n" +
+			"a=fingerprint:sha-1 4A:AD:B9:B1:3F:82:18:3B:54:02:12:DF:3E:5D:49:6B:19:E5:7C:AB\r\n" +
+			"a=setup:actpass\r\n" +
+			"m=audio 9 UDP/TLS/RTP/SAVP 0\r\n" +
+			"a=tls-id:abc3de65cddef001be82\r\n" +
+			"m=video 9 UDP/TLS/RTP/SAVP 31\r\n" +
+			"a=setup:passive\r\n" +
+			"a=tls-id:short\r\n"))
+		require.NoError(t, err)
+
+		fingerprints, err := desc.Fingerprints(0)
+		require.NoError(t, err)
+		require.Len(t, fingerprints, 1)
+		require.Equal(t, "sha-1", fingerprints[0].Hash)
+
+		setup, err := desc.Setup(0)
+		require.NoError(t, err)
+		require.Equal(t, ActPass, setup)
+		setup, err = desc.Setup(1)
+		require.NoError(t, err)
+		require.Equal(t, Passive, setup)
+
+		id, err := desc.Media[0].TLSID()
+		require.NoError(t, err)
+		require.Equal(t, TLSID("abc3de65cddef001be82"), id)
+		_, err = desc.Media[1].TLSID()
+		require.Equal(t, ErrBadSyntax, err)
+
+		generated, err := NewTLSID()
+		require.NoError(t, err)
+		_, err = TLSID("").Parse(string(generated))
+		require.NoError(t, err)
+	})
+}
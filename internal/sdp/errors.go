@@ -9,4 +9,19 @@ var (
 	ErrUnknownNetType          = errors.New("received unsupported o=<nettype> value")
 	ErrUnknownAddrType         = errors.New("received unsupported o=<addrtype> value")
 	ErrUnknownEncryptionMethod = errors.New("received unknown encryption method")
+	ErrUnknownPayloadType      = errors.New("dynamic payload type has no rtpmap attribute")
+	ErrUnknownHashFunction     = errors.New("received unsupported fingerprint hash function")
+	ErrFingerprintMismatch     = errors.New("certificate doesn't match any advertised fingerprint")
+	ErrUnknownCryptoSuite      = errors.New("unsupported SRTP crypto suite")
+	ErrNoCommonCryptoSuite     = errors.New("none of the offered SRTP crypto suites is supported")
+	ErrUnknownMID              = errors.New("group refers to a non-existing mid")
+	ErrNoTransport             = errors.New("media has no transport of its own nor a bundled one")
+	ErrFieldOrder              = errors.New("field is out of order")
+	ErrDuplicateField          = errors.New("field must not be repeated")
+	ErrMissingField            = errors.New("compulsory field is missing")
+	ErrOriginChanged           = errors.New("origin changed within the same session")
+	ErrVersionRegressed        = errors.New("session version is lower than the previous one")
+	ErrVersionNotBumped        = errors.New("description changed without bumping the session version")
+	ErrNoFormatsLeft           = errors.New("media must have at least a single format")
+	ErrBadAddress              = errors.New("connection address doesn't match its type")
 )
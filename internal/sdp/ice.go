@@ -0,0 +1,334 @@
+package sdp
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CandidateType is the type of the ICE candidate. See RFC 8445 5.1.1
+type CandidateType string
+
+const (
+	Host            CandidateType = "host"
+	ServerReflexive CandidateType = "srflx"
+	PeerReflexive   CandidateType = "prflx"
+	Relayed         CandidateType = "relay"
+)
+
+// CandidateExtension is an extension attribute of the candidate, e.g. tcptype or
+// generation
+type CandidateExtension struct {
+	Key   string
+	Value string
+}
+
+// Candidate is the value of the candidate attribute, following the grammar:
+//
+//	candidate-attribute = "candidate" ":" foundation SP component-id SP
+//	                      transport SP priority SP connection-address SP
+//	                      port SP cand-type [SP rel-addr] [SP rel-port]
+//	                      *(SP cand-extension)
+//
+// See RFC 8839 5.1.
+type Candidate struct {
+	Foundation string
+	Component  int
+	// Transport is usually UDP, the case is kept as received
+	Transport string
+	Priority  uint32
+	Address   string
+	Port      int
+	Type      CandidateType
+	// RelAddr and RelPort are the related address and port. They're always present
+	// together: RelAddr is empty if both are omitted, otherwise RelPort is written even
+	// if zero (browsers hide the related address as raddr 0.0.0.0 rport 0)
+	RelAddr    string
+	RelPort    int
+	Extensions []CandidateExtension
+}
+
+func (c Candidate) Parse(value string) (Candidate, error) {
+	fields := strings.Fields(value)
+	if len(fields) < 8 || fields[6] != "typ" {
+		return c, ErrBadSyntax
+	}
+
+	c.Foundation = fields[0]
+	if len(c.Foundation) == 0 || len(c.Foundation) > 32 {
+		return c, ErrBadSyntax
+	}
+
+	var err error
+	if c.Component, err = strconv.Atoi(fields[1]); err != nil || c.Component < 1 || c.Component > 256 {
+		return c, ErrBadSyntax
+	}
+
+	c.Transport = fields[2]
+
+	priority, err := strconv.ParseUint(fields[3], 10, 32)
+	if err != nil || priority == 0 {
+		return c, ErrBadSyntax
+	}
+
+	c.Priority, c.Address = uint32(priority), fields[4]
+
+	if c.Port, err = parsePort(fields[5]); err != nil {
+		return c, err
+	}
+
+	c.Type, fields = CandidateType(fields[7]), fields[8:]
+
+	if len(fields) >= 2 && fields[0] == "raddr" {
+		if len(fields) < 4 || fields[2] != "rport" {
+			return c, ErrBadSyntax
+		}
+
+		if c.RelPort, err = parsePort(fields[3]); err != nil {
+			return c, err
+		}
+
+		c.RelAddr, fields = fields[1], fields[4:]
+	} else if len(fields) >= 2 && fields[0] == "rport" {
+		return c, ErrBadSyntax
+	}
+
+	if len(fields)%2 != 0 {
+		// extensions always come in name-value pairs
+		return c, ErrBadSyntax
+	}
+
+	if len(fields) > 0 {
+		c.Extensions = make([]CandidateExtension, 0, len(fields)/2)
+		for i := 0; i < len(fields); i += 2 {
+			c.Extensions = append(c.Extensions, CandidateExtension{Key: fields[i], Value: fields[i+1]})
+		}
+	}
+
+	return c, nil
+}
+
+// Extension returns the value of the first extension with the key
+func (c Candidate) Extension(key string) (value string, found bool) {
+	for _, ext := range c.Extensions {
+		if ext.Key == key {
+			return ext.Value, true
+		}
+	}
+
+	return "", false
+}
+
+func (c Candidate) AppendTo(buff []byte) []byte {
+	buff = append(buff, c.Foundation...)
+	buff = append(buff, ' ')
+	buff = strconv.AppendInt(buff, int64(c.Component), 10)
+	buff = append(buff, ' ')
+	buff = append(buff, c.Transport...)
+	buff = append(buff, ' ')
+	buff = strconv.AppendUint(buff, uint64(c.Priority), 10)
+	buff = append(buff, ' ')
+	buff = append(buff, c.Address...)
+	buff = append(buff, ' ')
+	buff = strconv.AppendInt(buff, int64(c.Port), 10)
+	buff = append(buff, " typ "...)
+	buff = append(buff, c.Type...)
+
+	if len(c.RelAddr) > 0 {
+		buff = append(buff, " raddr "...)
+		buff = append(buff, c.RelAddr...)
+		buff = append(buff, " rport "...)
+		buff = strconv.AppendInt(buff, int64(c.RelPort), 10)
+	}
+
+	for _, ext := range c.Extensions {
+		buff = append(buff, ' ')
+		buff = append(buff, ext.Key...)
+		buff = append(buff, ' ')
+		buff = append(buff, ext.Value...)
+	}
+
+	return buff
+}
+
+// Candidates returns the ICE candidates of the media in the order of appearance
+func (m Media) Candidates() ([]Candidate, error) {
+	var candidates []Candidate
+
+	for _, attr := range m.Attributes {
+		if attr.Key != "candidate" {
+			continue
+		}
+
+		candidate, err := Candidate{}.Parse(attr.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates, nil
+}
+
+// AddCandidate appends the candidate to the media attributes. If the end-of-candidates
+// attribute is present, the candidate is inserted before it
+func (m *Media) AddCandidate(candidate Candidate) {
+	attr := Attribute{Key: "candidate", Value: string(candidate.AppendTo(nil))}
+	attrs := make([]Attribute, 0, len(m.Attributes)+1)
+	inserted := false
+
+	for _, a := range m.Attributes {
+		if a.Key == "end-of-candidates" && !inserted {
+			attrs = append(attrs, attr)
+			inserted = true
+		}
+
+		attrs = append(attrs, a)
+	}
+
+	if !inserted {
+		attrs = append(attrs, attr)
+	}
+
+	m.Attributes = attrs
+}
+
+// ICECredentials are the username fragment and the password of the ICE agent
+type ICECredentials struct {
+	Ufrag string
+	Pwd   string
+}
+
+// ICECredentials returns the credentials of the i-th media. Media-level attributes
+// take precedence over the session-level ones. See RFC 8839 5.4
+func (d Description) ICECredentials(i int) (creds ICECredentials, found bool) {
+	media := d.Media[i]
+
+	if creds.Ufrag, found = media.Attribute("ice-ufrag"); !found {
+		creds.Ufrag, found = d.Session.Attribute("ice-ufrag")
+	}
+
+	if !found {
+		return creds, false
+	}
+
+	if creds.Pwd, found = media.Attribute("ice-pwd"); !found {
+		creds.Pwd, found = d.Session.Attribute("ice-pwd")
+	}
+
+	return creds, found
+}
+
+// ICEOptions returns the ICE options of the i-th media. Media-level options take
+// precedence over the session-level ones
+func (d Description) ICEOptions(i int) []string {
+	options, found := d.Media[i].Attribute("ice-options")
+	if !found {
+		options, _ = d.Session.Attribute("ice-options")
+	}
+
+	return strings.Fields(options)
+}
+
+// Trickle reports whether the party supports trickle ICE for the i-th media. See
+// RFC 8840 4
+func (d Description) Trickle(i int) bool {
+	for _, option := range d.ICEOptions(i) {
+		if option == "trickle" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// EndOfCandidates reports whether the party has gathered all candidates for the
+// i-th media. The attribute may appear at the session level, covering all media
+func (d Description) EndOfCandidates(i int) bool {
+	_, found := d.Media[i].Attribute("end-of-candidates")
+	if !found {
+		_, found = d.Session.Attribute("end-of-candidates")
+	}
+
+	return found
+}
+
+// ICELite reports whether the party is a lite ICE implementation
+func (s Session) ICELite() bool {
+	_, found := s.Attribute("ice-lite")
+	return found
+}
+
+const (
+	// FragmentContentType is the media type of SDP fragments. See RFC 8839 9
+	FragmentContentType = "application/sdpfrag"
+	// TricklePackage is the Info-Package carrying SDP fragments in INFO requests. See
+	// RFC 8840 9
+	TricklePackage = "trickle-ice"
+)
+
+// Fragment is the SDP fragment carried by the trickle-ice INFO requests. It consists
+// of session-level attributes (e.g. ice-ufrag and ice-pwd) followed by media
+// descriptions, which are identified by the mid attribute or their order
+type Fragment struct {
+	Attributes []Attribute
+	Media      []Media
+}
+
+// Parse parses the SDP fragment. Session-level lines other than attributes aren't
+// allowed in fragments
+func (f Fragment) Parse(data []byte) (Fragment, error) {
+	desc, err := NewParser().Parse(data)
+	if err != nil {
+		return f, err
+	}
+
+	session := desc.Session
+	session.Attributes = nil
+	if !session.empty() {
+		return f, ErrBadSyntax
+	}
+
+	f.Attributes, f.Media = desc.Session.Attributes, desc.Media
+
+	return f, nil
+}
+
+// Description returns the fragment as a description, so the ICE helpers can be used
+func (f Fragment) Description() Description {
+	return Description{
+		Session: Session{Attributes: f.Attributes},
+		Media:   f.Media,
+	}
+}
+
+// Marshal returns the fragment in the wire format
+func (f Fragment) Marshal() []byte {
+	return f.AppendTo(make([]byte, 0, 256))
+}
+
+func (f Fragment) AppendTo(buff []byte) []byte {
+	buff = appendAttributes(buff, f.Attributes)
+
+	for _, media := range f.Media {
+		buff = media.AppendTo(buff)
+	}
+
+	return buff
+}
+
+func (s Session) empty() bool {
+	return len(s.Protocol) == 0 && s.Originator == Origin{} && len(s.Name) == 0 &&
+		len(s.Info) == 0 && len(s.URI) == 0 && len(s.Email) == 0 && len(s.Phone) == 0 &&
+		len(s.ConnectionInfo) == 0 && len(s.BandwidthInfo) == 0 && len(s.Times) == 0 &&
+		s.EncryptionKey == EncryptionKey{} && len(s.Attributes) == 0
+}
+
+func parsePort(value string) (int, error) {
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 0 || port > 65535 {
+		return 0, ErrBadSyntax
+	}
+
+	return port, nil
+}
@@ -0,0 +1,136 @@
+package sdp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestICE(t *testing.T) {
+	t.Run("candidates", func(t *testing.T) {
+		for _, tc := range []struct {
+			Value string
+			Want  Candidate
+		}{
+			{
+				Value: "1 1 UDP 2130706431 10.0.1.17 8998 typ host",
+				Want: Candidate{
+					Foundation: "1", Component: 1, Transport: "UDP", Priority: 2130706431,
+					Address: "10.0.1.17", Port: 8998, Type: Host,
+				},
+			},
+			{
+				Value: "2 1 UDP 1694498815 192.0.2.3 45664 typ srflx raddr 10.0.1.17 rport 8998",
+				Want: Candidate{
+					Foundation: "2", Component: 1, Transport: "UDP", Priority: 1694498815,
+					Address: "192.0.2.3", Port: 45664, Type: ServerReflexive,
+					RelAddr: "10.0.1.17", RelPort: 8998,
+				},
+			},
+			{
+				Value: "2 1 UDP 1694498815 192.0.2.3 45664 typ srflx raddr 0.0.0.0 rport 0",
+				Want: Candidate{
+					Foundation: "2", Component: 1, Transport: "UDP", Priority: 1694498815,
+					Address: "192.0.2.3", Port: 45664, Type: ServerReflexive,
+					RelAddr: "0.0.0.0", RelPort: 0,
+				},
+			},
+			{
+				Value: "3 2 TCP 1518280447 2001:db8::1 9 typ host tcptype active generation 0",
+				Want: Candidate{
+					Foundation: "3", Component: 2, Transport: "TCP", Priority: 1518280447,
+					Address: "2001:db8::1", Port: 9, Type: Host,
+					Extensions: []CandidateExtension{{"tcptype", "active"}, {"generation", "0"}},
+				},
+			},
+		} {
+			candidate, err := Candidate{}.Parse(tc.Value)
+			require.NoError(t, err)
+			require.Equal(t, tc.Want, candidate)
+			require.Equal(t, tc.Value, string(candidate.AppendTo(nil)))
+		}
+
+		for _, value := range []string{
+			"1 1 UDP 2130706431 10.0.1.17 8998 host",
+			"1 0 UDP 2130706431 10.0.1.17 8998 typ host",
+			"1 1 UDP 2130706431 10.0.1.17 70000 typ host",
+			"1 1 UDP 2130706431 10.0.1.17 8998 typ host generation",
+			"2 1 UDP 1694498815 192.0.2.3 45664 typ srflx raddr 10.0.1.17",
+			"2 1 UDP 1694498815 192.0.2.3 45664 typ srflx raddr 10.0.1.17 generation 0",
+			"2 1 UDP 1694498815 192.0.2.3 45664 typ srflx rport 8998",
+		} {
+			_, err := Candidate{}.Parse(value)
+			require.Equal(t, ErrBadSyntax, err, value)
+		}
+	})
+
+	t.Run("credentials and options", func(t *testing.T) {
+		desc, err := NewParser().Parse([]byte("v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=-\r\n" +
+			"t=0 0\r\n" +
+			"a=ice-lite\r\n" +
+			"a=ice-ufrag:8hhY\r\n" +
+			"a=ice-pwd:asd88fgpdd777uzjYhagZg\r\n" +
+			"a=ice-options:trickle\r\n" +
+			"m=audio 49170 RTP/AVP 0\r\n" +
+			"a=candidate:1 1 UDP 2130706431 10.0.1.17 49170 typ host\r\n" +
+			"a=end-of-candidates\r\n" +
+			"m=video 51372 RTP/AVP 31\r\n" +
+			"a=ice-ufrag:fOrV\r\n" +
+			"a=ice-pwd:JmQZIRd2bOz7RSk4JFh1z5zP\r\n" +
+			"a=ice-options:ice2\r\n"))
+		require.NoError(t, err)
+
+		require.True(t, desc.Session.ICELite())
+		creds, found := desc.ICECredentials(0)
+		require.True(t, found)
+		require.Equal(t, ICECredentials{Ufrag: "8hhY", Pwd: "asd88fgpdd777uzjYhagZg"}, creds)
+		creds, found = desc.ICECredentials(1)
+		require.True(t, found)
+		require.Equal(t, ICECredentials{Ufrag: "fOrV", Pwd: "JmQZIRd2bOz7RSk4JFh1z5zP"}, creds)
+
+		require.True(t, desc.Trickle(0))
+		require.False(t, desc.Trickle(1))
+		require.True(t, desc.EndOfCandidates(0))
+		require.False(t, desc.EndOfCandidates(1))
+
+		media := desc.Media[0]
+		media.AddCandidate(Candidate{
+			Foundation: "2", Component: 1, Transport: "UDP", Priority: 1694498815,
+			Address: "192.0.2.3", Port: 45664, Type: ServerReflexive,
+			RelAddr: "10.0.1.17", RelPort: 49170,
+		})
+		candidates, err := media.Candidates()
+		require.NoError(t, err)
+		require.Len(t, candidates, 2)
+		require.Equal(t, "end-of-candidates", media.Attributes[len(media.Attributes)-1].Key)
+		require.Len(t, desc.Media[0].Attributes, 2, "shared attributes must be kept intact")
+	})
+
+	t.Run("trickle fragment", func(t *testing.T) {
+		sample := "a=ice-ufrag:8hhY\r\n" +
+			"a=ice-pwd:asd88fgpdd777uzjYhagZg\r\n" +
+			"m=audio 9 RTP/AVP 0\r\n" +
+			"a=mid:1\r\n" +
+			"a=candidate:1 1 UDP 2130706431 10.0.1.17 5000 typ host\r\n" +
+			"a=candidate:2 1 UDP 1658497328 192.0.2.1 5000 typ srflx raddr 10.0.1.17 rport 5000\r\n" +
+			"a=end-of-candidates\r\n"
+		fragment, err := Fragment{}.Parse([]byte(sample))
+		require.NoError(t, err)
+		require.Len(t, fragment.Media, 1)
+		require.Equal(t, sample, string(fragment.Marshal()))
+
+		desc := fragment.Description()
+		creds, found := desc.ICECredentials(0)
+		require.True(t, found)
+		require.Equal(t, "8hhY", creds.Ufrag)
+		require.True(t, desc.EndOfCandidates(0))
+		candidates, err := desc.Media[0].Candidates()
+		require.NoError(t, err)
+		require.Len(t, candidates, 2)
+
+		_, err = Fragment{}.Parse([]byte("v=0\r\na=ice-ufrag:8hhY\r\n"))
+		require.Equal(t, ErrBadSyntax, err)
+	})
+}
@@ -0,0 +1,283 @@
+package sdp
+
+import "strconv"
+
+const crlf = "\r\n"
+
+// Marshal returns the description in the wire format. Fields are written in the order
+// RFC 8866 5 requires, each line is terminated with CRLF
+func (d Description) Marshal() []byte {
+	return d.AppendTo(make([]byte, 0, 512))
+}
+
+// AppendTo appends the description in the wire format to the buffer
+func (d Description) AppendTo(buff []byte) []byte {
+	buff = d.Session.AppendTo(buff)
+
+	for _, media := range d.Media {
+		buff = media.AppendTo(buff)
+	}
+
+	return buff
+}
+
+func (s Session) AppendTo(buff []byte) []byte {
+	protocol := s.Protocol
+	if len(protocol) == 0 {
+		protocol = "0"
+	}
+
+	buff = appendStringField(buff, 'v', protocol)
+	buff = append(buff, "o="...)
+	buff = s.Originator.AppendTo(buff)
+	buff = append(buff, crlf...)
+
+	name := s.Name
+	if len(name) == 0 {
+		// session name must never be empty. See RFC 8866 5.3
+		name = "-"
+	}
+
+	buff = appendStringField(buff, 's', name)
+	buff = appendOptionalField(buff, 'i', s.Info)
+	buff = appendOptionalField(buff, 'u', s.URI)
+	buff = appendOptionalField(buff, 'e', s.Email)
+	buff = appendOptionalField(buff, 'p', s.Phone)
+	buff = appendConnectionInfo(buff, s.ConnectionInfo)
+	buff = appendBandwidths(buff, s.BandwidthInfo)
+
+	if len(s.Times) == 0 {
+		// the time description is compulsory, so the permanent session is assumed
+		buff = append(buff, "t=0 0"+crlf...)
+	}
+
+	for _, t := range s.Times {
+		buff = t.AppendTo(buff)
+	}
+
+	buff = appendEncryptionKey(buff, s.EncryptionKey)
+
+	return appendAttributes(buff, s.Attributes)
+}
+
+func (m Media) AppendTo(buff []byte) []byte {
+	buff = append(buff, "m="...)
+	buff = append(buff, m.Type...)
+	buff = append(buff, ' ')
+	buff = strconv.AppendInt(buff, int64(m.Port), 10)
+
+	if m.PortCount > 0 {
+		buff = append(buff, '/')
+		buff = strconv.AppendInt(buff, int64(m.PortCount), 10)
+	}
+
+	buff = append(buff, ' ')
+	buff = append(buff, m.Proto...)
+
+	for _, format := range m.Formats {
+		buff = append(buff, ' ')
+		buff = append(buff, format...)
+	}
+
+	buff = append(buff, crlf...)
+	buff = appendOptionalField(buff, 'i', m.Title)
+	buff = appendConnectionInfo(buff, m.ConnectionInfo)
+	buff = appendBandwidths(buff, m.BandwidthInfo)
+	buff = appendEncryptionKey(buff, m.EncryptionKey)
+
+	return appendAttributes(buff, m.Attributes)
+}
+
+// AppendTo appends the t= field together with its r= and z= fields
+func (t TimeDescription) AppendTo(buff []byte) []byte {
+	buff = append(buff, "t="...)
+	buff = strconv.AppendUint(buff, t.Start, 10)
+	buff = append(buff, ' ')
+	buff = strconv.AppendUint(buff, t.Stop, 10)
+	buff = append(buff, crlf...)
+
+	for _, repeat := range t.Repeats {
+		buff = append(buff, "r="...)
+		buff = repeat.AppendTo(buff)
+		buff = append(buff, crlf...)
+	}
+
+	if len(t.ZoneAdjustments) == 0 {
+		return buff
+	}
+
+	buff = append(buff, "z="...)
+
+	for i, adjustment := range t.ZoneAdjustments {
+		if i > 0 {
+			buff = append(buff, ' ')
+		}
+
+		buff = strconv.AppendUint(buff, adjustment.Time, 10)
+		buff = append(buff, ' ')
+		buff = adjustment.Offset.AppendTo(buff)
+	}
+
+	return append(buff, crlf...)
+}
+
+func (r Repeat) AppendTo(buff []byte) []byte {
+	buff = r.Interval.AppendTo(buff)
+	buff = append(buff, ' ')
+	buff = r.Duration.AppendTo(buff)
+
+	for _, offset := range r.Offsets {
+		buff = append(buff, ' ')
+		buff = offset.AppendTo(buff)
+	}
+
+	return buff
+}
+
+func (t TypedTime) AppendTo(buff []byte) []byte {
+	if seconds := unitSeconds(t.Unit); seconds != 0 && t.Seconds%seconds == 0 {
+		buff = strconv.AppendInt(buff, t.Seconds/seconds, 10)
+		return append(buff, t.Unit)
+	}
+
+	return strconv.AppendInt(buff, t.Seconds, 10)
+}
+
+func (o Origin) AppendTo(buff []byte) []byte {
+	username := o.Username
+	if len(username) == 0 {
+		username = "-"
+	}
+
+	buff = append(buff, username...)
+	buff = append(buff, ' ')
+	buff = strconv.AppendUint(buff, o.SessId, 10)
+	buff = append(buff, ' ')
+	buff = strconv.AppendUint(buff, o.SessVersion, 10)
+	buff = append(buff, ' ')
+	buff = append(buff, o.NetType...)
+	buff = append(buff, ' ')
+	buff = append(buff, o.AddrType...)
+	buff = append(buff, ' ')
+
+	return append(buff, o.UnicastAddress...)
+}
+
+func (c ConnectionInfo) AppendTo(buff []byte) []byte {
+	buff = append(buff, c.NetType...)
+	buff = append(buff, ' ')
+	buff = append(buff, c.AddrType...)
+	buff = append(buff, ' ')
+	buff = append(buff, c.Address...)
+
+	if c.AddrType == IP6 {
+		// IP6 multicast addresses have no TTL, so the only number is the range
+		if c.AddrRange > 0 {
+			buff = append(buff, '/')
+			buff = strconv.AppendInt(buff, int64(c.AddrRange), 10)
+		}
+
+		return buff
+	}
+
+	if c.TTL > 0 {
+		buff = append(buff, '/')
+		buff = strconv.AppendInt(buff, int64(c.TTL), 10)
+
+		if c.AddrRange > 0 {
+			buff = append(buff, '/')
+			buff = strconv.AppendInt(buff, int64(c.AddrRange), 10)
+		}
+	}
+
+	return buff
+}
+
+func (b Bandwidth) AppendTo(buff []byte) []byte {
+	buff = append(buff, b.Type...)
+	buff = append(buff, ':')
+
+	if len(b.Raw) > 0 {
+		return append(buff, b.Raw...)
+	}
+
+	return strconv.AppendInt(buff, int64(b.Value), 10)
+}
+
+func (e EncryptionKey) AppendTo(buff []byte) []byte {
+	buff = append(buff, e.Method...)
+
+	if len(e.Key) > 0 {
+		buff = append(buff, ':')
+		buff = append(buff, e.Key...)
+	}
+
+	return buff
+}
+
+func (a Attribute) AppendTo(buff []byte) []byte {
+	buff = append(buff, a.Key...)
+
+	if len(a.Value) > 0 {
+		buff = append(buff, ':')
+		buff = append(buff, a.Value...)
+	}
+
+	return buff
+}
+
+func appendStringField(buff []byte, key byte, value string) []byte {
+	buff = append(buff, key, '=')
+	buff = append(buff, value...)
+
+	return append(buff, crlf...)
+}
+
+func appendOptionalField(buff []byte, key byte, value string) []byte {
+	if len(value) == 0 {
+		return buff
+	}
+
+	return appendStringField(buff, key, value)
+}
+
+func appendConnectionInfo(buff []byte, infos []ConnectionInfo) []byte {
+	for _, info := range infos {
+		buff = append(buff, "c="...)
+		buff = info.AppendTo(buff)
+		buff = append(buff, crlf...)
+	}
+
+	return buff
+}
+
+func appendBandwidths(buff []byte, bandwidths []Bandwidth) []byte {
+	for _, bandwidth := range bandwidths {
+		buff = append(buff, "b="...)
+		buff = bandwidth.AppendTo(buff)
+		buff = append(buff, crlf...)
+	}
+
+	return buff
+}
+
+func appendEncryptionKey(buff []byte, key EncryptionKey) []byte {
+	if len(key.Method) == 0 {
+		return buff
+	}
+
+	buff = append(buff, "k="...)
+	buff = key.AppendTo(buff)
+
+	return append(buff, crlf...)
+}
+
+func appendAttributes(buff []byte, attrs []Attribute) []byte {
+	for _, attr := range attrs {
+		buff = append(buff, "a="...)
+		buff = attr.AppendTo(buff)
+		buff = append(buff, crlf...)
+	}
+
+	return buff
+}
@@ -0,0 +1,89 @@
+package sdp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshal(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		for name, sample := range map[string]string{
+			"rfc sample": "v=0\r\n" +
+				"o=jdoe 3724394400 3724394405 IN IP4 198.51.100.1\r\n" +
+				"s=Call to John Smith\r\n" +
+				"i=SDP Offer #1\r\n" +
+				"u=http://www.jdoe.example.com/home.html\r\n" +
+				"e=Jane Doe <jane@jdoe.example.com>\r\n" +
+				"p=+1 617 555-6011\r\n" +
+				"c=IN IP4 198.51.100.1\r\n" +
+				"t=0 0\r\n" +
+				"m=audio 49170 RTP/AVP 0\r\n" +
+				"m=audio 49180 RTP/AVP 0\r\n" +
+				"m=video 51372 RTP/AVP 99\r\n" +
+				"c=IN IP6 2001:db8::2\r\n" +
+				"a=rtpmap:99 h263-1998/90000\r\n",
+			"all the fields": "v=0\r\n" +
+				"o=- 2890844526 2890842807 IN IP4 10.47.16.5\r\n" +
+				"s=SDP Seminar\r\n" +
+				"c=IN IP4 224.2.17.12/127/2\r\n" +
+				"c=IN IP6 ff15::101/3\r\n" +
+				"b=CT:128\r\n" +
+				"t=3034423619 3042462419\r\n" +
+				"r=7d 1h 0 25h\r\n" +
+				"r=604800 3600 0 90000\r\n" +
+				"z=2882844526 -1h 2898848070 0\r\n" +
+				"t=0 0\r\n" +
+				"k=prompt\r\n" +
+				"a=recvonly\r\n" +
+				"a=tool:blu\r\n" +
+				"m=audio 49170/2 RTP/AVP 0 8 101\r\n" +
+				"i=main audio\r\n" +
+				"c=IN IP4 224.2.1.1/127\r\n" +
+				"b=AS:64\r\n" +
+				"k=base64:c2VjcmV0\r\n" +
+				"a=rtpmap:101 telephone-event/8000\r\n" +
+				"a=fmtp:101 0-15\r\n",
+		} {
+			t.Run(name, func(t *testing.T) {
+				desc, err := NewParser().Parse([]byte(sample))
+				require.NoError(t, err)
+				require.Equal(t, sample, string(desc.Marshal()))
+			})
+		}
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		desc := Description{
+			Session: Session{
+				Originator: Origin{
+					SessId:         1,
+					SessVersion:    1,
+					NetType:        IN,
+					AddrType:       IP4,
+					UnicastAddress: "192.0.2.1",
+				},
+				ConnectionInfo: []ConnectionInfo{{NetType: IN, AddrType: IP4, Address: "192.0.2.1"}},
+			},
+			Media: []Media{{
+				Type:    Audio,
+				Port:    4000,
+				Proto:   RTPAVP,
+				Formats: []string{"0"},
+			}},
+		}
+		require.Equal(t, "v=0\r\n"+
+			"o=- 1 1 IN IP4 192.0.2.1\r\n"+
+			"s=-\r\n"+
+			"c=IN IP4 192.0.2.1\r\n"+
+			"t=0 0\r\n"+
+			"m=audio 4000 RTP/AVP 0\r\n", string(desc.Marshal()))
+	})
+
+	t.Run("append", func(t *testing.T) {
+		desc, err := NewParser().Parse([]byte("v=0\r\no=- 1 1 IN IP4 127.0.0.1\r\ns=-\r\nt=0 0\r\n"))
+		require.NoError(t, err)
+		buff := desc.AppendTo([]byte("prefix\r\n"))
+		require.Equal(t, "prefix\r\nv=0\r\no=- 1 1 IN IP4 127.0.0.1\r\ns=-\r\nt=0 0\r\n", string(buff))
+	})
+}
@@ -0,0 +1,131 @@
+package sdp
+
+import (
+	"strconv"
+	"strings"
+)
+
+type MediaType string
+
+const (
+	Audio       MediaType = "audio"
+	Video       MediaType = "video"
+	Text        MediaType = "text"
+	Application MediaType = "application"
+	Message     MediaType = "message"
+	Image       MediaType = "image"
+)
+
+type TransportProto string
+
+const (
+	UDP            TransportProto = "udp"
+	RTPAVP         TransportProto = "RTP/AVP"
+	RTPAVPF        TransportProto = "RTP/AVPF"
+	RTPSAVP        TransportProto = "RTP/SAVP"
+	RTPSAVPF       TransportProto = "RTP/SAVPF"
+	UDPTLSRTPSAVP  TransportProto = "UDP/TLS/RTP/SAVP"
+	UDPTLSRTPSAVPF TransportProto = "UDP/TLS/RTP/SAVPF"
+	UDPTL          TransportProto = "udptl"
+	TCP            TransportProto = "TCP"
+	UDPDTLSSCTP    TransportProto = "UDP/DTLS/SCTP"
+)
+
+// RTP reports whether the formats of the media are RTP payload types
+func (t TransportProto) RTP() bool {
+	return strings.Contains(string(t), "RTP/")
+}
+
+// Parse parses the m= field, following the grammar:
+//
+//	media-field = %s"m" "=" media SP port ["/" integer] SP proto 1*(SP fmt)
+//
+// Media types and transport protocols are extensible, so unknown ones are accepted.
+func (m Media) Parse(value string) (Media, error) {
+	return m.parse(value, nil)
+}
+
+// parse parses the media line, appending the formats to the slice
+func (m Media) parse(value string, formats []string) (Media, error) {
+	sp := strings.IndexByte(value, ' ')
+	if sp <= 0 {
+		return m, ErrBadSyntax
+	}
+
+	m.Type, value = MediaType(value[:sp]), value[sp+1:]
+
+	sp = strings.IndexByte(value, ' ')
+	if sp == -1 {
+		return m, ErrBadSyntax
+	}
+
+	port := value[:sp]
+	value = value[sp+1:]
+
+	if slash := strings.IndexByte(port, '/'); slash != -1 {
+		count, err := strconv.Atoi(port[slash+1:])
+		if err != nil || count <= 0 {
+			return m, ErrBadSyntax
+		}
+
+		m.PortCount, port = count, port[:slash]
+	}
+
+	var err error
+	if m.Port, err = strconv.Atoi(port); err != nil || m.Port < 0 || m.Port > 65535 {
+		return m, ErrBadSyntax
+	}
+
+	sp = strings.IndexByte(value, ' ')
+	if sp <= 0 {
+		// at least a single format is compulsory
+		return m, ErrBadSyntax
+	}
+
+	m.Proto, value = TransportProto(value[:sp]), value[sp+1:]
+	m.Formats = appendFields(formats, value)
+	if len(m.Formats) == 0 {
+		return m, ErrBadSyntax
+	}
+
+	return m, nil
+}
+
+// appendFields appends the space-separated fields of the value, as strings.Fields
+// does, but without allocating a new slice
+func appendFields(fields []string, value string) []string {
+	for len(value) > 0 {
+		sp := strings.IndexByte(value, ' ')
+		if sp == -1 {
+			return append(fields, value)
+		}
+
+		if sp > 0 {
+			fields = append(fields, value[:sp])
+		}
+
+		value = value[sp+1:]
+	}
+
+	return fields
+}
+
+// Attribute returns the value of the first media-level attribute with the key
+func (m Media) Attribute(key string) (value string, found bool) {
+	return findAttribute(m.Attributes, key)
+}
+
+// Attribute returns the value of the first session-level attribute with the key
+func (s Session) Attribute(key string) (value string, found bool) {
+	return findAttribute(s.Attributes, key)
+}
+
+func findAttribute(attrs []Attribute, key string) (value string, found bool) {
+	for _, attr := range attrs {
+		if attr.Key == key {
+			return attr.Value, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,100 @@
+package sdp
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"math"
+	"strconv"
+	"time"
+)
+
+// NewOrigin creates the origin of a new session. The session id is a random 63-bit
+// value, so sessions created at the same moment still get distinct ids, and the
+// version is the NTP timestamp, as RFC 8866 5.2 suggests, so it keeps growing even
+// across restarts
+func NewOrigin(username string, addrType AddrType, address string) Origin {
+	if len(username) == 0 {
+		username = "-"
+	}
+
+	return Origin{
+		Username:       username,
+		SessId:         randomSessId(),
+		SessVersion:    TimeToNTP(time.Now()),
+		NetType:        IN,
+		AddrType:       addrType,
+		UnicastAddress: address,
+	}
+}
+
+func randomSessId() uint64 {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// the system randomness is never expected to fail, but still the id must be set
+		return uint64(time.Now().UnixNano()) & math.MaxInt64
+	}
+
+	return binary.BigEndian.Uint64(b[:]) & math.MaxInt64
+}
+
+// Next returns the origin with the version incremented. It must be used each time
+// a modified description is sent
+func (o Origin) Next() Origin {
+	o.SessVersion++
+	return o
+}
+
+// SameSession reports whether both origins identify the same session. All the
+// fields except the version must be equal. See RFC 8866 5.2
+func (o Origin) SameSession(other Origin) bool {
+	return o.Username == other.Username && o.SessId == other.SessId &&
+		o.NetType == other.NetType && o.AddrType == other.AddrType &&
+		o.UnicastAddress == other.UnicastAddress
+}
+
+// Change is the kind of difference between two subsequent descriptions of a session
+type Change uint8
+
+const (
+	// SessionRefresh is the same description sent again, e.g. in a session timer
+	// re-INVITE
+	SessionRefresh Change = iota
+	// SessionModified is the description with a new version
+	SessionModified
+)
+
+// ChangeFrom tells whether the description is a modification of the previously
+// received one or just a refresh. Descriptions of another session, regressed
+// versions and changes without bumping the version are reported as errors, as
+// RFC 3264 8 prohibits them
+func (d Description) ChangeFrom(prev Description) (Change, error) {
+	origin, prevOrigin := d.Session.Originator, prev.Session.Originator
+
+	switch {
+	case !origin.SameSession(prevOrigin):
+		return 0, ErrOriginChanged
+	case origin.SessVersion > prevOrigin.SessVersion:
+		return SessionModified, nil
+	case origin.SessVersion < prevOrigin.SessVersion:
+		return 0, ErrVersionRegressed
+	case !bytes.Equal(d.Marshal(), prev.Marshal()):
+		return 0, ErrVersionNotBumped
+	default:
+		return SessionRefresh, nil
+	}
+}
+
+// parseDigits parses the unsigned 64-bit integer, which may be made of digits only
+func parseDigits(value string) (uint64, error) {
+	if len(value) == 0 || value[0] == '+' {
+		return 0, ErrBadSyntax
+	}
+
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, ErrBadSyntax
+	}
+
+	return n, nil
+}
@@ -0,0 +1,63 @@
+package sdp
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrigin(t *testing.T) {
+	t.Run("numeric fields", func(t *testing.T) {
+		origin, err := Origin{}.Parse("- 18446744073709551615 0 IN IP4 192.0.2.1")
+		require.NoError(t, err)
+		require.Equal(t, uint64(18446744073709551615), origin.SessId)
+		require.Equal(t, "- 18446744073709551615 0 IN IP4 192.0.2.1", string(origin.AppendTo(nil)))
+
+		for _, value := range []string{
+			"- 18446744073709551616 0 IN IP4 192.0.2.1",
+			"- -1 0 IN IP4 192.0.2.1",
+			"- +1 0 IN IP4 192.0.2.1",
+			"- 1 x IN IP4 192.0.2.1",
+		} {
+			_, err := Origin{}.Parse(value)
+			require.Equal(t, ErrBadSyntax, err, value)
+		}
+	})
+
+	t.Run("versioning", func(t *testing.T) {
+		origin := NewOrigin("", IP4, "192.0.2.1")
+		require.Equal(t, "-", origin.Username)
+		require.LessOrEqual(t, origin.SessId, uint64(math.MaxInt64))
+		require.Equal(t, time.Now().Year(), NTPTime(origin.SessVersion).Year())
+		// sessions created at the same moment are still distinct
+		require.NotEqual(t, origin.SessId, NewOrigin("", IP4, "192.0.2.1").SessId)
+
+		next := origin.Next()
+		require.Equal(t, origin.SessVersion+1, next.SessVersion)
+		require.True(t, next.SameSession(origin))
+
+		prev := Description{Session: Session{Originator: origin, Name: "a"}}
+		desc := prev
+		change, err := desc.ChangeFrom(prev)
+		require.NoError(t, err)
+		require.Equal(t, SessionRefresh, change)
+
+		desc.Session.Name = "b"
+		_, err = desc.ChangeFrom(prev)
+		require.Equal(t, ErrVersionNotBumped, err)
+
+		desc.Session.Originator = next
+		change, err = desc.ChangeFrom(prev)
+		require.NoError(t, err)
+		require.Equal(t, SessionModified, change)
+
+		_, err = prev.ChangeFrom(desc)
+		require.Equal(t, ErrVersionRegressed, err)
+
+		desc.Session.Originator.SessId++
+		_, err = desc.ChangeFrom(prev)
+		require.Equal(t, ErrOriginChanged, err)
+	})
+}
@@ -2,44 +2,101 @@ package sdp
 
 import (
 	"bytes"
+	"errors"
 
 	"github.com/indigo-web/utils/uf"
 )
 
-type Parser struct{}
+// Parser parses session descriptions. The lenient one accepts fields in any order
+// and doesn't require compulsory ones, so broken devices are still understood. The
+// strict one enforces the order and the cardinality of fields as RFC 8866 5 defines
+// and reports errors as SyntaxError, carrying the line number
+type Parser struct {
+	strict bool
+}
 
+// NewParser returns the lenient parser
 func NewParser() Parser {
 	return Parser{}
 }
 
-func (Parser) Parse(data []byte) (desc Description, err error) {
-	session := Session{}
-	var value string
+// NewStrictParser returns the validating parser
+func NewStrictParser() Parser {
+	return Parser{strict: true}
+}
 
-	for len(data) > 0 {
-		if len(data) < 2 {
-			return desc, ErrIncompleteData
-		}
+// Parse parses the description. Strings of the description refer to the data, so it
+// must not be modified as long as the description is in use
+func (p Parser) Parse(data []byte) (desc Description, err error) {
+	err = p.ParseInto(&desc, data)
 
-		if data[1] != '=' {
-			return desc, ErrBadSyntax
-		}
+	return desc, err
+}
 
+// ParseInto parses the description into desc, reusing its slices, so parsing into the
+// same description repeatedly doesn't allocate once they've grown large enough. As
+// with Parse, strings refer to the data. The previous contents of desc are overwritten
+// in place, so descriptions sharing slices with it (e.g. its copies) must not be used
+// afterwards
+func (p Parser) ParseInto(desc *Description, data []byte) (err error) {
+	var (
+		value string
+		line  int
+		order orderChecker
+	)
+
+	if p.strict {
+		order = newOrderChecker()
+
+		defer func() {
+			var syntaxErr SyntaxError
+			if err != nil && !errors.As(err, &syntaxErr) {
+				err = SyntaxError{Line: line, Err: err}
+			}
+		}()
+	}
+
+	session := &desc.Session
+	*session = Session{
+		ConnectionInfo: session.ConnectionInfo[:0],
+		BandwidthInfo:  session.BandwidthInfo[:0],
+		Times:          session.Times[:0],
+		Attributes:     session.Attributes[:0],
+	}
+	desc.Media = desc.Media[:0]
+
+	for len(data) > 0 {
 		if data[0] == 'm' {
 			// media starts here
 			break
 		}
 
+		line++
+
+		if len(data) < 2 {
+			return ErrIncompleteData
+		}
+
+		if data[1] != '=' {
+			return ErrBadSyntax
+		}
+
 		key := data[0]
 		value, data = parseValue(data[2:])
 
+		if p.strict {
+			if err = order.check(key, line); err != nil {
+				return err
+			}
+		}
+
 		switch key {
 		case 'v':
 			session.Protocol = value
 		case 'o':
 			session.Originator, err = session.Originator.Parse(value)
 			if err != nil {
-				return desc, err
+				return err
 			}
 		case 's':
 			session.Name = value
@@ -54,84 +111,144 @@ func (Parser) Parse(data []byte) (desc Description, err error) {
 		case 'c':
 			connInfo, err := ConnectionInfo{}.Parse(value)
 			if err != nil {
-				return desc, err
+				return err
 			}
 
 			session.ConnectionInfo = append(session.ConnectionInfo, connInfo)
 		case 'b':
 			bwInfo, err := Bandwidth{}.Parse(value)
 			if err != nil {
-				return desc, err
+				return err
 			}
 
 			session.BandwidthInfo = append(session.BandwidthInfo, bwInfo)
+		case 't':
+			session.Times = extend(session.Times)
+			last := &session.Times[len(session.Times)-1]
+			*last, err = TimeDescription{
+				Repeats:         last.Repeats[:0],
+				ZoneAdjustments: last.ZoneAdjustments[:0],
+			}.Parse(value)
+			if err != nil {
+				return err
+			}
+		case 'r':
+			if len(session.Times) == 0 {
+				// repeat times are meaningless without the time description
+				return ErrBadSyntax
+			}
+
+			repeat, err := Repeat{}.Parse(value)
+			if err != nil {
+				return err
+			}
+
+			last := &session.Times[len(session.Times)-1]
+			last.Repeats = append(last.Repeats, repeat)
 		case 'z':
-			session.TimeZoneAdjustments = append(session.TimeZoneAdjustments, value)
+			if len(session.Times) == 0 {
+				return ErrBadSyntax
+			}
+
+			adjustments, err := parseZoneAdjustments(value)
+			if err != nil {
+				return err
+			}
+
+			last := &session.Times[len(session.Times)-1]
+			last.ZoneAdjustments = append(last.ZoneAdjustments, adjustments...)
 		case 'k':
 			session.EncryptionKey, err = EncryptionKey{}.Parse(value)
 			if err != nil {
-				return desc, err
+				return err
 			}
 		case 'a':
 			session.Attributes = append(session.Attributes, Attribute{}.Parse(value))
 		default:
-			return desc, ErrUnrecognizedKey
+			return ErrUnrecognizedKey
 		}
 	}
 
-	desc.Session = session
-
-	if len(data) == 0 {
-		return desc, nil
-	}
-
-	var media Media
+	var media *Media
 
 	for len(data) > 0 {
+		line++
+
 		if len(data) < 2 {
-			return desc, ErrIncompleteData
+			return ErrIncompleteData
 		}
 
 		if data[1] != '=' {
-			return desc, ErrBadSyntax
-		}
-
-		if data[0] == 'm' && media.Name != "" {
-			// the next media block description has begun
-			desc.Media = append(desc.Media, media)
-			media = Media{}
-			continue
+			return ErrBadSyntax
 		}
 
 		key := data[0]
 		value, data = parseValue(data[2:])
 
+		if p.strict {
+			if err = order.check(key, line); err != nil {
+				return err
+			}
+		}
+
 		switch key {
 		case 'm':
-			media.Name = value
+			// the next media block description has begun
+			desc.Media = extend(desc.Media)
+			media = &desc.Media[len(desc.Media)-1]
+			*media, err = Media{
+				ConnectionInfo: media.ConnectionInfo[:0],
+				BandwidthInfo:  media.BandwidthInfo[:0],
+				Attributes:     media.Attributes[:0],
+			}.parse(value, media.Formats[:0])
+			if err != nil {
+				return err
+			}
 		case 'i':
 			media.Title = value
 		case 'c':
 			connInfo, err := ConnectionInfo{}.Parse(value)
 			if err != nil {
-				return desc, err
+				return err
 			}
 
 			media.ConnectionInfo = append(media.ConnectionInfo, connInfo)
 		case 'b':
-			media.BandwidthInfo = append(media.BandwidthInfo, value)
+			bwInfo, err := Bandwidth{}.Parse(value)
+			if err != nil {
+				return err
+			}
+
+			media.BandwidthInfo = append(media.BandwidthInfo, bwInfo)
 		case 'k':
-			media.EncryptionKey = value
+			media.EncryptionKey, err = EncryptionKey{}.Parse(value)
+			if err != nil {
+				return err
+			}
 		case 'a':
-			media.Attributes = append(media.Attributes, value)
+			media.Attributes = append(media.Attributes, Attribute{}.Parse(value))
 		default:
-			return desc, ErrUnrecognizedKey
+			return ErrUnrecognizedKey
 		}
 	}
 
-	desc.Media = append(desc.Media, media)
+	if p.strict {
+		err = order.finish()
+	}
+
+	return err
+}
+
+// extend grows the slice by a single element. The element left behind by the previous
+// parsing is kept as is within the capacity, so its own slices may be reused
+func extend[T any](slice []T) []T {
+	if len(slice) < cap(slice) {
+		return slice[:len(slice)+1]
+	}
+
+	var zero T
 
-	return desc, nil
+	return append(slice, zero)
 }
 
 func parseValue(data []byte) (value string, rest []byte) {
@@ -140,7 +257,7 @@ func parseValue(data []byte) (value string, rest []byte) {
 	if lf >= 0 {
 		rest, data = data[lf+1:], data[:lf]
 
-		if data[len(data)-1] == '\r' {
+		if len(data) > 0 && data[len(data)-1] == '\r' {
 			data = data[:len(data)-1]
 		}
 	}
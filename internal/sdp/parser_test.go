@@ -2,6 +2,7 @@ package sdp
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -17,7 +18,7 @@ func TestParser(t *testing.T) {
 			"c=IN IP4 224.2.17.12/127\r\n" +
 			"b=CT:128\r\n" +
 			"k=clear:qwerty\r\n" +
-			//"t=2873397496 2873404696\r\n" +
+			"t=2873397496 2873404696\r\n" +
 			"a=recvonly\r\n" +
 			"a=hello:world\r\n" +
 			"m=audio 49170 RTP/AVP 0\r\n" +
@@ -28,8 +29,8 @@ func TestParser(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, "0", desc.Session.Protocol)
 		require.Equal(t, "jdoe", desc.Session.Originator.Username)
-		require.Equal(t, "2890844526", desc.Session.Originator.SessId)
-		require.Equal(t, "2890842807", desc.Session.Originator.SessVersion)
+		require.Equal(t, uint64(2890844526), desc.Session.Originator.SessId)
+		require.Equal(t, uint64(2890842807), desc.Session.Originator.SessVersion)
 		require.Equal(t, IN, desc.Session.Originator.NetType)
 		require.Equal(t, IP4, desc.Session.Originator.AddrType)
 		require.Equal(t, "10.47.16.5", desc.Session.Originator.UnicastAddress)
@@ -48,13 +49,133 @@ func TestParser(t *testing.T) {
 		require.Equal(t, 128, desc.Session.BandwidthInfo[0].Value)
 		require.Equal(t, Clear, desc.Session.EncryptionKey.Method)
 		require.Equal(t, "qwerty", desc.Session.EncryptionKey.Key)
+		require.Equal(t, []TimeDescription{{Start: 2873397496, Stop: 2873404696}}, desc.Session.Times)
 		require.Equal(t, []Attribute{{Key: "recvonly"}, {Key: "hello", Value: "world"}}, desc.Session.Attributes)
 		require.Equal(t, 2, len(desc.Media), "must be exactly 2 media blocks")
 		media := desc.Media[0]
-		require.Equal(t, "audio 49170 RTP/AVP 0", media.Name)
+		require.Equal(t, Audio, media.Type)
+		require.Equal(t, 49170, media.Port)
+		require.Zero(t, media.PortCount)
+		require.Equal(t, RTPAVP, media.Proto)
+		require.Equal(t, []string{"0"}, media.Formats)
 		media = desc.Media[1]
-		require.Equal(t, "video 51372 RTP/AVP 99", media.Name)
-		require.Equal(t, []string{"rtpmap:99 h263-1998/90000"}, media.Attributes)
+		require.Equal(t, Video, media.Type)
+		require.Equal(t, 51372, media.Port)
+		require.Equal(t, RTPAVP, media.Proto)
+		require.Equal(t, []string{"99"}, media.Formats)
+		require.Equal(t, []Attribute{{Key: "rtpmap", Value: "99 h263-1998/90000"}}, media.Attributes)
+	})
+
+	t.Run("media-level fields", func(t *testing.T) {
+		sample := []byte("v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=-\r\n" +
+			"t=0 0\r\n" +
+			"m=audio 49170/2 RTP/AVP 0 8 101\r\n" +
+			"i=main audio\r\n" +
+			"c=IN IP4 224.2.1.1/127/2\r\n" +
+			"b=AS:64\r\n" +
+			"k=base64:c2VjcmV0\r\n" +
+			"a=sendrecv\r\n" +
+			"m=application 9 UDP/DTLS/SCTP webrtc-datachannel\r\n")
+		desc, err := NewParser().Parse(sample)
+		require.NoError(t, err)
+		require.Len(t, desc.Media, 2)
+
+		media := desc.Media[0]
+		require.Equal(t, 2, media.PortCount)
+		require.Equal(t, []string{"0", "8", "101"}, media.Formats)
+		require.Equal(t, "main audio", media.Title)
+		require.Equal(t, []Bandwidth{{Type: AS, Value: 64}}, media.BandwidthInfo)
+		require.Equal(t, EncryptionKey{Method: Base64, Key: "c2VjcmV0"}, media.EncryptionKey)
+		require.Equal(t, 2, media.ConnectionInfo[0].AddrRange)
+		_, found := media.Attribute("sendrecv")
+		require.True(t, found)
+
+		media = desc.Media[1]
+		require.Equal(t, Application, media.Type)
+		require.Equal(t, UDPDTLSSCTP, media.Proto)
+		require.False(t, media.Proto.RTP())
+		require.Equal(t, []string{"webrtc-datachannel"}, media.Formats)
+	})
+
+	t.Run("malformed media", func(t *testing.T) {
+		for sample, want := range map[string]error{
+			"m=audio\r\n":                          ErrBadSyntax,
+			"m=audio 49170 RTP/AVP\r\n":            ErrBadSyntax,
+			"m=audio 49170/0 RTP/AVP 0\r\n":        ErrBadSyntax,
+			"m=audio 70000 RTP/AVP 0\r\n":          ErrBadSyntax,
+			"m=audio port RTP/AVP 0\r\n":           ErrBadSyntax,
+			"m=audio 0 RTP/AVP 0\r\nk=rot13:x\r\n": ErrUnknownEncryptionMethod,
+			"m=audio 0 RTP/AVP 0\r\nt=0 0\r\n":     ErrUnrecognizedKey,
+			"m=audio 0 RTP/AVP 0\r\nb=AS\r\n":      ErrBadSyntax,
+		} {
+			_, err := NewParser().Parse([]byte("v=0\r\ns=-\r\n" + sample))
+			require.Equalf(t, want, err, "sample: %q", sample)
+		}
+	})
+}
+
+func TestTimeDescriptions(t *testing.T) {
+	t.Run("repeats and zone adjustments", func(t *testing.T) {
+		sample := []byte("v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=-\r\n" +
+			"t=3034423619 3042462419\r\n" +
+			"r=7d 1h 0 25h\r\n" +
+			"r=604800 3600 0 90000\r\n" +
+			"z=2882844526 -1h 2898848070 0\r\n" +
+			"t=0 0\r\n")
+		desc, err := NewParser().Parse(sample)
+		require.NoError(t, err)
+		require.Len(t, desc.Session.Times, 2)
+
+		timeDesc := desc.Session.Times[0]
+		require.Equal(t, uint64(3034423619), timeDesc.Start)
+		require.Equal(t, uint64(3042462419), timeDesc.Stop)
+		require.Equal(t, 1996, NTPTime(timeDesc.Start).Year())
+		require.Len(t, timeDesc.Repeats, 2)
+		require.Equal(t, Repeat{
+			Interval: TypedTime{Seconds: 604800, Unit: 'd'},
+			Duration: TypedTime{Seconds: 3600, Unit: 'h'},
+			Offsets:  []TypedTime{{Seconds: 0}, {Seconds: 90000, Unit: 'h'}},
+		}, timeDesc.Repeats[0])
+		require.Equal(t, Repeat{
+			Interval: TypedTime{Seconds: 604800},
+			Duration: TypedTime{Seconds: 3600},
+			Offsets:  []TypedTime{{Seconds: 0}, {Seconds: 90000}},
+		}, timeDesc.Repeats[1])
+		require.Equal(t, 7*24*time.Hour, timeDesc.Repeats[0].Interval.Duration())
+		require.Equal(t, []ZoneAdjustment{
+			{Time: 2882844526, Offset: TypedTime{Seconds: -3600, Unit: 'h'}},
+			{Time: 2898848070, Offset: TypedTime{Seconds: 0}},
+		}, timeDesc.ZoneAdjustments)
+
+		require.True(t, desc.Session.Times[1].Permanent())
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		for _, sample := range []string{
+			"t=0\r\n",
+			"t=now 0\r\n",
+			"r=7d 1h 0\r\n",
+			"t=0 0\r\nr=7d 1h\r\n",
+			"t=0 0\r\nr=7x 1h 0\r\n",
+			"t=0 0\r\nz=2882844526\r\n",
+			"t=0 0\r\nr=-7d 1h 0\r\n",
+			"t=0 0\r\nr=7d -1h 0\r\n",
+			"t=0 0\r\nr=7d 1h +0\r\n",
+			"t=0 0\r\nr=0 1h 0\r\n",
+			"t=0 0\r\nr=0d 1h 0\r\n",
+			"t=0 0\r\nr=7d 1h 106751991167301d\r\n",
+			"t=0 0\r\nr=7d 1h 9223372036854775808\r\n",
+			"t=0 0\r\nr=7d 1h 0\r\nz=2882844526 --1h\r\n",
+			"t=0 0\r\nr=7d 1h 0\r\nz=2882844526 +1h\r\n",
+			"t=0 0\r\nr=7d 1h 0\r\nz=2882844526 -\r\n",
+		} {
+			_, err := NewParser().Parse([]byte("v=0\r\n" + sample))
+			require.Equalf(t, ErrBadSyntax, err, "sample: %q", sample)
+		}
 	})
 }
 
@@ -97,3 +218,168 @@ func TestParseAddr(t *testing.T) {
 		require.Equal(t, baseAddr, addr)
 	})
 }
+
+func TestStrictParser(t *testing.T) {
+	const valid = "v=0\r\n" +
+		"o=jdoe 2890844526 2890842807 IN IP4 10.47.16.5\r\n" +
+		"s=SDP Seminar\r\n" +
+		"i=A Seminar on the session description protocol\r\n" +
+		"e=j.doe@example.com (Jane Doe)\r\n" +
+		"e=jane@example.com\r\n" +
+		"b=CT:128\r\n" +
+		"t=2873397496 2873404696\r\n" +
+		"r=7d 1h 0 25h\r\n" +
+		"t=2873407496 2873414696\r\n" +
+		"r=7d 1h 0\r\n" +
+		"z=2882844526 -1h\r\n" +
+		"a=recvonly\r\n" +
+		"m=audio 49170 RTP/AVP 0\r\n" +
+		"c=IN IP4 224.2.17.12/127\r\n" +
+		"m=video 51372 RTP/AVP 99\r\n" +
+		"i=Slides\r\n" +
+		"c=IN IP4 224.2.17.13/127\r\n" +
+		"b=AS:64\r\n" +
+		"a=rtpmap:99 h263-1998/90000\r\n"
+
+	t.Run("valid", func(t *testing.T) {
+		strict, err := NewStrictParser().Parse([]byte(valid))
+		require.NoError(t, err)
+		lenient, err := NewParser().Parse([]byte(valid))
+		require.NoError(t, err)
+		require.Equal(t, lenient, strict)
+	})
+
+	t.Run("multiple time descriptions", func(t *testing.T) {
+		const sample = "v=0\r\n" +
+			"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+			"s=-\r\n" +
+			"c=IN IP4 127.0.0.1\r\n" +
+			"t=2873397496 2873404696\r\n" +
+			"r=7d 1h 0 25h\r\n" +
+			"z=2882844526 -1h 2898848070 0\r\n" +
+			"t=2873407496 2873414696\r\n" +
+			"r=7d 1h 0\r\n" +
+			"z=2882844526 -1h\r\n" +
+			"t=0 0\r\n" +
+			"m=audio 49170 RTP/AVP 0\r\n"
+
+		desc, err := NewStrictParser().Parse([]byte(sample))
+		require.NoError(t, err)
+		require.Len(t, desc.Session.Times, 3)
+		require.Len(t, desc.Session.Times[0].Repeats, 1)
+		require.Len(t, desc.Session.Times[0].ZoneAdjustments, 2)
+		require.Len(t, desc.Session.Times[1].ZoneAdjustments, 1)
+		require.Empty(t, desc.Session.Times[2].ZoneAdjustments)
+
+		for _, tc := range []struct {
+			Sample string
+			Line   int
+		}{
+			{"v=0\r\no=- 0 0 IN IP4 127.0.0.1\r\ns=-\r\nz=2882844526 -1h\r\nt=0 0\r\n", 4},
+			// z= belongs to the repeat description, so it can't follow t= directly
+			{"v=0\r\no=- 0 0 IN IP4 127.0.0.1\r\ns=-\r\nt=0 0\r\nz=2882844526 -1h\r\n", 5},
+			{"v=0\r\no=- 0 0 IN IP4 127.0.0.1\r\ns=-\r\nt=0 0\r\nr=7d 1h 0\r\nz=2882844526 -1h\r\nz=2882844526 0\r\n", 7},
+			{"v=0\r\no=- 0 0 IN IP4 127.0.0.1\r\ns=-\r\nt=0 0\r\nr=7d 1h 0\r\nz=2882844526 -1h\r\nr=7d 1h 0\r\n", 7},
+		} {
+			_, err := NewStrictParser().Parse([]byte(tc.Sample))
+			require.Equalf(t, SyntaxError{Line: tc.Line, Err: ErrFieldOrder}, err, "sample: %q", tc.Sample)
+		}
+	})
+
+	t.Run("empty values", func(t *testing.T) {
+		for _, sample := range []string{
+			"v=0\no=- 0 0 IN IP4 127.0.0.1\ns=-\ni=\nt=0 0\nm=audio 0 RTP/AVP 0\nc=IN IP4 0.0.0.0\n",
+			"v=0\r\no=- 0 0 IN IP4 127.0.0.1\r\ns=-\r\ni=\r\nt=0 0\r\nm=audio 0 RTP/AVP 0\r\nc=IN IP4 0.0.0.0\r\n",
+		} {
+			desc, err := NewStrictParser().Parse([]byte(sample))
+			require.NoError(t, err)
+			require.Empty(t, desc.Session.Info)
+		}
+
+		_, err := NewStrictParser().Parse([]byte("v=0\no=- 0 0 IN IP4 127.0.0.1\ns=-\nt=\n"))
+		require.Equal(t, SyntaxError{Line: 4, Err: ErrBadSyntax}, err)
+	})
+
+	t.Run("violations", func(t *testing.T) {
+		for _, tc := range []struct {
+			Sample string
+			Line   int
+			Err    error
+		}{
+			{"o=- 0 0 IN IP4 127.0.0.1\r\nv=0\r\n", 2, ErrFieldOrder},
+			{"v=0\r\no=- 0 0 IN IP4 127.0.0.1\r\ns=a\r\ns=b\r\nt=0 0\r\n", 4, ErrDuplicateField},
+			{"v=0\r\no=- 0 0 IN IP4 127.0.0.1\r\ns=-\r\nr=7d 1h 0\r\nt=0 0\r\n", 4, ErrFieldOrder},
+			{"v=0\r\no=- 0 0 IN IP4 127.0.0.1\r\ns=-\r\nt=0 0\r\na=x\r\nk=clear:x\r\n", 6, ErrFieldOrder},
+			{"v=0\r\no=- 0 0 IN IP4 127.0.0.1\r\ns=-\r\nc=IN IP4 0.0.0.0\r\n", 4, ErrMissingField},
+			{"v=0\r\no=- 0 0 IN IP4 127.0.0.1\r\ns=-\r\nt=0 0\r\nm=audio 0 RTP/AVP 0\r\n", 5, ErrMissingField},
+			{
+				"v=0\r\no=- 0 0 IN IP4 127.0.0.1\r\ns=-\r\nt=0 0\r\nm=audio 0 RTP/AVP 0\r\nc=IN IP4 0.0.0.0\r\n" +
+					"m=video 0 RTP/AVP 31\r\na=inactive\r\nm=text 0 RTP/AVP 98\r\n",
+				7, ErrMissingField,
+			},
+			{
+				"v=0\r\no=- 0 0 IN IP4 127.0.0.1\r\ns=-\r\nc=IN IP4 0.0.0.0\r\nt=0 0\r\nm=audio 0 RTP/AVP 0\r\n" +
+					"a=sendonly\r\nb=AS:64\r\n",
+				8, ErrFieldOrder,
+			},
+			{"v=0\r\no=- 0 0 IN IP4 127.0.0.1\r\ns=-\r\nc=IN IP4 0.0.0.0\r\nt=0\r\n", 5, ErrBadSyntax},
+		} {
+			_, err := NewStrictParser().Parse([]byte(tc.Sample))
+			require.Equalf(t, SyntaxError{Line: tc.Line, Err: tc.Err}, err, "sample: %q", tc.Sample)
+			require.ErrorIs(t, err, tc.Err)
+		}
+	})
+}
+
+func TestParseInto(t *testing.T) {
+	const (
+		offer = "v=0\r\n" +
+			"o=alice 1 1 IN IP4 192.0.2.1\r\n" +
+			"s=-\r\n" +
+			"c=IN IP4 192.0.2.1\r\n" +
+			"t=0 0\r\n" +
+			"r=7d 1h 0 25h\r\n" +
+			"a=sendrecv\r\n" +
+			"m=audio 4000 RTP/AVP 0 8 101\r\n" +
+			"b=AS:64\r\n" +
+			"a=rtpmap:0 PCMU/8000\r\n" +
+			"a=rtpmap:8 PCMA/8000\r\n" +
+			"a=rtpmap:101 telephone-event/8000\r\n" +
+			"m=video 4002 RTP/AVP 96\r\n" +
+			"c=IN IP4 192.0.2.2\r\n" +
+			"a=rtpmap:96 H264/90000\r\n"
+		answer = "v=0\r\n" +
+			"o=bob 2 2 IN IP4 198.51.100.1\r\n" +
+			"s=-\r\n" +
+			"t=0 0\r\n" +
+			"m=audio 5000 RTP/AVP 8\r\n" +
+			"c=IN IP4 198.51.100.1\r\n" +
+			"a=rtpmap:8 PCMA/8000\r\n"
+	)
+
+	parser := NewParser()
+
+	t.Run("reused description", func(t *testing.T) {
+		var desc Description
+		for _, sample := range []string{offer, answer, offer} {
+			require.NoError(t, parser.ParseInto(&desc, []byte(sample)))
+
+			expected, err := parser.Parse([]byte(sample))
+			require.NoError(t, err)
+			// nothing is left behind by the previous parsing
+			require.Equal(t, string(expected.Marshal()), string(desc.Marshal()))
+		}
+	})
+
+	t.Run("no allocations", func(t *testing.T) {
+		data := []byte(offer)
+		var desc Description
+		require.NoError(t, parser.ParseInto(&desc, data))
+
+		allocs := testing.AllocsPerRun(100, func() {
+			_ = parser.ParseInto(&desc, data)
+		})
+		// repeat times are the only ones allocated anew
+		require.LessOrEqual(t, allocs, 2.0)
+	})
+}
@@ -10,7 +10,7 @@ func BenchmarkParser(b *testing.B) {
 		"u=http://www.example.com/seminars/sdp.pdf\r\n" +
 		"e=j.doe@example.com (Jane Doe)\r\n" +
 		"c=IN IP4 224.2.17.12/127\r\n" +
-		//"t=2873397496 2873404696\r\n" +
+		"t=2873397496 2873404696\r\n" +
 		"a=recvonly\r\n" +
 		"m=audio 49170 RTP/AVP 0\r\n" +
 		"m=video 51372 RTP/AVP 99\r\n" +
@@ -19,10 +19,23 @@ func BenchmarkParser(b *testing.B) {
 	b.Run("rfc sample", func(b *testing.B) {
 		parser := NewParser()
 		b.SetBytes(int64(len(rfcSample)))
+		b.ReportAllocs()
 		b.ResetTimer()
 
 		for i := 0; i < b.N; i++ {
 			_, _ = parser.Parse(rfcSample)
 		}
 	})
+
+	b.Run("rfc sample reused", func(b *testing.B) {
+		parser := NewParser()
+		var desc Description
+		b.SetBytes(int64(len(rfcSample)))
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			_ = parser.ParseInto(&desc, rfcSample)
+		}
+	})
 }
@@ -0,0 +1,230 @@
+package sdp
+
+import (
+	"strconv"
+	"strings"
+)
+
+// The operations below modify the description in place, but never the slices it
+// shares with its copies. See the package documentation
+
+// SetConnection replaces the connection info and the port of the i-th media
+func (d *Description) SetConnection(i int, info ConnectionInfo, port int) {
+	media := d.mutableMedia(i)
+	media.ConnectionInfo = []ConnectionInfo{info}
+	media.Port = port
+}
+
+// SetMediaDirection replaces the direction attribute of the i-th media
+func (d *Description) SetMediaDirection(i int, dir Direction) {
+	d.mutableMedia(i).SetDirection(dir)
+}
+
+// AddCodec appends the codec to the formats of the i-th media together with its rtpmap
+// and fmtp attributes
+func (d *Description) AddCodec(i int, codec Codec) {
+	media := d.mutableMedia(i)
+	format := strconv.Itoa(int(codec.PayloadType))
+	media.Formats = append(media.Formats[:len(media.Formats):len(media.Formats)], format)
+
+	rtpmap := RTPMap{
+		PayloadType: codec.PayloadType,
+		Encoding:    codec.Name,
+		ClockRate:   codec.ClockRate,
+		Channels:    codec.Channels,
+	}
+	attrs := append(media.Attributes[:len(media.Attributes):len(media.Attributes)],
+		Attribute{Key: "rtpmap", Value: string(rtpmap.AppendTo(nil))})
+
+	if len(codec.Params) > 0 {
+		fmtp := FormatParams{PayloadType: codec.PayloadType, Params: codec.Params}
+		attrs = append(attrs, Attribute{Key: "fmtp", Value: string(fmtp.AppendTo(nil))})
+	}
+
+	media.Attributes = attrs
+}
+
+// RemoveFormat removes the format from the i-th media together with the attributes
+// referring to it (rtpmap, fmtp and rtcp-fb). As at least one format is compulsory,
+// the last one can't be removed
+func (d *Description) RemoveFormat(i int, format string) error {
+	formats := make([]string, 0, len(d.Media[i].Formats))
+	for _, f := range d.Media[i].Formats {
+		if f != format {
+			formats = append(formats, f)
+		}
+	}
+
+	if len(formats) == 0 {
+		return ErrNoFormatsLeft
+	}
+
+	media := d.mutableMedia(i)
+	media.Formats = formats
+	media.Attributes = filterAttributes(media.Attributes, func(attr Attribute) bool {
+		switch attr.Key {
+		case "rtpmap", "fmtp", "rtcp-fb":
+			pt, _, _ := strings.Cut(attr.Value, " ")
+			return pt != format
+		default:
+			return true
+		}
+	})
+
+	return nil
+}
+
+// StripAttributes removes the attributes with the keys at the session level and from
+// all the media
+func (d *Description) StripAttributes(keys ...string) {
+	d.Session.Attributes = stripAttributes(d.Session.Attributes, keys)
+
+	for i := range d.Media {
+		d.StripMediaAttributes(i, keys...)
+	}
+}
+
+// StripMediaAttributes removes the attributes with the keys from the i-th media
+func (d *Description) StripMediaAttributes(i int, keys ...string) {
+	media := d.mutableMedia(i)
+	media.Attributes = stripAttributes(media.Attributes, keys)
+}
+
+// mutableMedia copies the media slice, so the i-th media may be modified without
+// affecting other descriptions
+func (d *Description) mutableMedia(i int) *Media {
+	media := make([]Media, len(d.Media))
+	copy(media, d.Media)
+	d.Media = media
+
+	return &d.Media[i]
+}
+
+func stripAttributes(attrs []Attribute, keys []string) []Attribute {
+	return filterAttributes(attrs, func(attr Attribute) bool {
+		for _, key := range keys {
+			if attr.Key == key {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+func filterAttributes(attrs []Attribute, keep func(Attribute) bool) []Attribute {
+	result := make([]Attribute, 0, len(attrs))
+	for _, attr := range attrs {
+		if keep(attr) {
+			result = append(result, attr)
+		}
+	}
+
+	return result
+}
+
+// MediaChange is the set of changes of a single media between two descriptions
+type MediaChange uint8
+
+const (
+	MediaAdded MediaChange = 1 << iota
+	MediaRejected
+	PortChanged
+	AddressChanged
+	CodecsChanged
+	DirectionChanged
+	ICERestart
+)
+
+// Has reports whether all the changes are present
+func (m MediaChange) Has(change MediaChange) bool {
+	return m&change == change
+}
+
+// MediaDiff describes changes of the media with the index
+type MediaDiff struct {
+	Index   int
+	Changes MediaChange
+}
+
+// Diff compares two subsequent descriptions of the session and reports the changes
+// of each media. Media without changes aren't listed. Codecs are compared by formats
+// and their rtpmap and fmtp attributes, addresses by the effective connection info.
+// ICE restart is detected by the change of credentials. See RFC 8839 4.4.1.1.1
+func Diff(prev, next Description) []MediaDiff {
+	var diffs []MediaDiff
+
+	for i := range next.Media {
+		var changes MediaChange
+
+		if i >= len(prev.Media) {
+			changes = MediaAdded
+		} else {
+			changes = diffMedia(prev, next, i)
+		}
+
+		if changes != 0 {
+			diffs = append(diffs, MediaDiff{Index: i, Changes: changes})
+		}
+	}
+
+	return diffs
+}
+
+func diffMedia(prev, next Description, i int) (changes MediaChange) {
+	before, after := prev.Media[i], next.Media[i]
+
+	if before.Port != 0 && after.Port == 0 {
+		changes |= MediaRejected
+	}
+
+	if before.Port != after.Port {
+		changes |= PortChanged
+	}
+
+	if connectionAddress(prev, before) != connectionAddress(next, after) {
+		changes |= AddressChanged
+	}
+
+	if codecSignature(before) != codecSignature(after) {
+		changes |= CodecsChanged
+	}
+
+	if prev.Direction(i) != next.Direction(i) {
+		changes |= DirectionChanged
+	}
+
+	prevCreds, prevFound := prev.ICECredentials(i)
+	nextCreds, nextFound := next.ICECredentials(i)
+	if prevFound && nextFound && prevCreds != nextCreds {
+		changes |= ICERestart
+	}
+
+	return changes
+}
+
+func connectionAddress(desc Description, media Media) string {
+	if infos := desc.connectionInfo(media); len(infos) > 0 {
+		return infos[0].Address
+	}
+
+	return ""
+}
+
+// codecSignature joins the formats with the values of rtpmap and fmtp attributes, so
+// codecs can be compared as a whole
+func codecSignature(media Media) string {
+	var b strings.Builder
+	b.WriteString(strings.Join(media.Formats, " "))
+
+	for _, attr := range media.Attributes {
+		if attr.Key == "rtpmap" || attr.Key == "fmtp" {
+			b.WriteByte('\n')
+			b.WriteString(attr.Key)
+			b.WriteByte(':')
+			b.WriteString(attr.Value)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,73 @@
+package sdp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const rewriteSample = "v=0\r\n" +
+	"o=- 1 1 IN IP4 198.51.100.1\r\n" +
+	"s=-\r\n" +
+	"c=IN IP4 198.51.100.1\r\n" +
+	"t=0 0\r\n" +
+	"a=ice-ufrag:8hhY\r\n" +
+	"a=ice-pwd:asd88fgpdd777uzjYhagZg\r\n" +
+	"m=audio 49170 RTP/AVP 0 18 101\r\n" +
+	"a=fmtp:18 annexb=no\r\n" +
+	"a=rtpmap:101 telephone-event/8000\r\n" +
+	"a=fmtp:101 0-15\r\n" +
+	"a=sendrecv\r\n" +
+	"m=video 51372 RTP/AVP 96\r\n" +
+	"a=rtpmap:96 VP8/90000\r\n" +
+	"a=rtcp-fb:96 nack\r\n" +
+	"a=x-custom:1\r\n"
+
+func TestRewrite(t *testing.T) {
+	original, err := NewParser().Parse([]byte(rewriteSample))
+	require.NoError(t, err)
+
+	desc := original
+	desc.SetConnection(0, ConnectionInfo{NetType: IN, AddrType: IP4, Address: "203.0.113.1", TTL: -1}, 30000)
+	require.NoError(t, desc.RemoveFormat(0, "18"))
+	desc.AddCodec(0, Codec{PayloadType: 8, Name: "PCMA", ClockRate: 8000})
+	desc.SetMediaDirection(0, SendOnly)
+	require.Equal(t, ErrNoFormatsLeft, desc.RemoveFormat(1, "96"))
+	desc.StripAttributes("x-custom", "ice-ufrag", "ice-pwd")
+
+	require.Equal(t, "v=0\r\n"+
+		"o=- 1 1 IN IP4 198.51.100.1\r\n"+
+		"s=-\r\n"+
+		"c=IN IP4 198.51.100.1\r\n"+
+		"t=0 0\r\n"+
+		"m=audio 30000 RTP/AVP 0 101 8\r\n"+
+		"c=IN IP4 203.0.113.1\r\n"+
+		"a=rtpmap:101 telephone-event/8000\r\n"+
+		"a=fmtp:101 0-15\r\n"+
+		"a=rtpmap:8 PCMA/8000\r\n"+
+		"a=sendonly\r\n"+
+		"m=video 51372 RTP/AVP 96\r\n"+
+		"a=rtpmap:96 VP8/90000\r\n"+
+		"a=rtcp-fb:96 nack\r\n", string(desc.Marshal()))
+	require.Equal(t, rewriteSample, string(original.Marshal()), "original must be kept intact")
+
+	t.Run("diff", func(t *testing.T) {
+		require.Empty(t, Diff(original, original))
+
+		require.Equal(t, []MediaDiff{{
+			Index:   0,
+			Changes: PortChanged | AddressChanged | CodecsChanged | DirectionChanged,
+		}}, Diff(original, desc))
+
+		next := original
+		next.Session.Attributes = []Attribute{{Key: "ice-ufrag", Value: "fOrV"}, {Key: "ice-pwd", Value: "JmQZIRd2bOz7RSk4JFh1z5zP"}}
+		next.Media = append(next.Media[:len(next.Media):len(next.Media)], Media{Type: Text, Port: 0, Proto: RTPAVP, Formats: []string{"98"}})
+		next.SetConnection(1, ConnectionInfo{NetType: IN, AddrType: IP4, Address: "198.51.100.1", TTL: -1}, 0)
+
+		diffs := Diff(original, next)
+		require.Len(t, diffs, 3)
+		require.True(t, diffs[0].Changes.Has(ICERestart))
+		require.Equal(t, MediaRejected|PortChanged|ICERestart, diffs[1].Changes)
+		require.Equal(t, MediaDiff{Index: 2, Changes: MediaAdded}, diffs[2])
+	})
+}
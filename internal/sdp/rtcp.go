@@ -0,0 +1,327 @@
+package sdp
+
+import (
+	"strconv"
+	"strings"
+)
+
+// RTCPFeedback is the value of the rtcp-fb attribute, following the grammar:
+//
+//	rtcp-fb-attribute = "a=rtcp-fb:" rtcp-fb-pt SP rtcp-fb-val
+//	rtcp-fb-pt        = "*" / fmt
+//
+// See RFC 4585 4.2.
+type RTCPFeedback struct {
+	PayloadType uint8
+	// Any is set for the wildcard payload type, which applies to all formats
+	Any bool
+	// Type is the feedback type, e.g. nack, ccm, goog-remb or transport-cc
+	Type string
+	// Param is the feedback parameter, e.g. pli for nack or fir for ccm. It may
+	// contain several space-separated tokens
+	Param string
+}
+
+func (r RTCPFeedback) Parse(value string) (RTCPFeedback, error) {
+	sp := strings.IndexByte(value, ' ')
+	if sp <= 0 {
+		return r, ErrBadSyntax
+	}
+
+	if pt := value[:sp]; pt == "*" {
+		r.Any = true
+	} else {
+		var err error
+		if r.PayloadType, err = parsePayloadType(pt); err != nil {
+			return r, err
+		}
+	}
+
+	value = strings.TrimLeft(value[sp+1:], " ")
+	r.Type, r.Param, _ = strings.Cut(value, " ")
+	if len(r.Type) == 0 {
+		return r, ErrBadSyntax
+	}
+
+	r.Param = strings.TrimSpace(r.Param)
+
+	return r, nil
+}
+
+func (r RTCPFeedback) AppendTo(buff []byte) []byte {
+	if r.Any {
+		buff = append(buff, '*')
+	} else {
+		buff = strconv.AppendUint(buff, uint64(r.PayloadType), 10)
+	}
+
+	buff = append(buff, ' ')
+	buff = append(buff, r.Type...)
+
+	if len(r.Param) > 0 {
+		buff = append(buff, ' ')
+		buff = append(buff, r.Param...)
+	}
+
+	return buff
+}
+
+// Applies reports whether the feedback applies to the payload type
+func (r RTCPFeedback) Applies(pt uint8) bool {
+	return r.Any || r.PayloadType == pt
+}
+
+// RTCPFeedback returns the rtcp-fb attributes of the media
+func (m Media) RTCPFeedback() ([]RTCPFeedback, error) {
+	var feedback []RTCPFeedback
+
+	for _, attr := range m.Attributes {
+		if attr.Key != "rtcp-fb" {
+			continue
+		}
+
+		fb, err := RTCPFeedback{}.Parse(attr.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		feedback = append(feedback, fb)
+	}
+
+	return feedback, nil
+}
+
+// FeedbackFor returns the feedback applying to the payload type, including the
+// wildcard one
+func (m Media) FeedbackFor(pt uint8) ([]RTCPFeedback, error) {
+	feedback, err := m.RTCPFeedback()
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := feedback[:0]
+	for _, fb := range feedback {
+		if fb.Applies(pt) {
+			filtered = append(filtered, fb)
+		}
+	}
+
+	return filtered, nil
+}
+
+// NegotiateFeedback returns the offered feedback, whose type and parameter are
+// supported. Payload types of the supported feedback are ignored
+func NegotiateFeedback(offered, supported []RTCPFeedback) []RTCPFeedback {
+	var result []RTCPFeedback
+
+	for _, fb := range offered {
+		for _, local := range supported {
+			if strings.EqualFold(fb.Type, local.Type) && strings.EqualFold(fb.Param, local.Param) {
+				result = append(result, fb)
+				break
+			}
+		}
+	}
+
+	return result
+}
+
+// Extmap is the value of the extmap attribute, following the grammar:
+//
+//	extmap = mapentry SP extensionname [SP extensionattributes]
+//	mapentry = "extmap:" 1*5DIGIT ["/" direction]
+//
+// See RFC 8285 8.
+type Extmap struct {
+	ID int
+	// Direction is empty, if it was omitted
+	Direction Direction
+	URI       string
+	// Attributes are the extension attributes, kept as is
+	Attributes string
+}
+
+func (e Extmap) Parse(value string) (Extmap, error) {
+	entry, value, found := strings.Cut(value, " ")
+	if !found {
+		return e, ErrBadSyntax
+	}
+
+	id, dir, found := strings.Cut(entry, "/")
+	if found {
+		if e.Direction = Direction(dir); !e.Direction.Valid() {
+			return e, ErrBadSyntax
+		}
+	}
+
+	var err error
+	if e.ID, err = strconv.Atoi(id); err != nil || !validExtmapID(e.ID) {
+		return e, ErrBadSyntax
+	}
+
+	e.URI, e.Attributes, _ = strings.Cut(strings.TrimLeft(value, " "), " ")
+	if len(e.URI) == 0 {
+		return e, ErrBadSyntax
+	}
+
+	e.Attributes = strings.TrimSpace(e.Attributes)
+
+	return e, nil
+}
+
+func (e Extmap) AppendTo(buff []byte) []byte {
+	buff = strconv.AppendInt(buff, int64(e.ID), 10)
+
+	if len(e.Direction) > 0 {
+		buff = append(buff, '/')
+		buff = append(buff, e.Direction...)
+	}
+
+	buff = append(buff, ' ')
+	buff = append(buff, e.URI...)
+
+	if len(e.Attributes) > 0 {
+		buff = append(buff, ' ')
+		buff = append(buff, e.Attributes...)
+	}
+
+	return buff
+}
+
+// validExtmapID reports whether the identifier fits the one-byte (1-14) or two-byte
+// (1-255) headers, or the range reserved for offers (4096-4351). See RFC 8285 5
+func validExtmapID(id int) bool {
+	return (id >= 1 && id <= 255 && id != 15) || (id >= 4096 && id <= 4351)
+}
+
+// Extmaps returns the header extensions of the i-th media. Session-level extensions
+// apply to every media, so they are listed first
+func (d Description) Extmaps(i int) ([]Extmap, error) {
+	var extmaps []Extmap
+
+	for _, attrs := range [][]Attribute{d.Session.Attributes, d.Media[i].Attributes} {
+		for _, attr := range attrs {
+			if attr.Key != "extmap" {
+				continue
+			}
+
+			extmap, err := Extmap{}.Parse(attr.Value)
+			if err != nil {
+				return nil, err
+			}
+
+			extmaps = append(extmaps, extmap)
+		}
+	}
+
+	return extmaps, nil
+}
+
+// NegotiateExtmaps returns the answered header extensions: the offered ones with the
+// supported URIs. Identifiers are kept, while directions are reversed, so they're
+// seen from the answerer's side. Identifiers of the range reserved for offers
+// (4096-4351) are remapped to the lowest free valid ones, as answers can't use that
+// range. Extensions that don't get a free identifier are dropped
+func NegotiateExtmaps(offered []Extmap, supported []string) []Extmap {
+	var (
+		result []Extmap
+		used   [256]bool
+	)
+
+	for _, extmap := range offered {
+		for _, uri := range supported {
+			if extmap.URI == uri {
+				extmap.Direction = extmap.Direction.Reverse()
+				result = append(result, extmap)
+				if extmap.ID < len(used) {
+					used[extmap.ID] = true
+				}
+
+				break
+			}
+		}
+	}
+
+	free := 1
+	kept := result[:0]
+
+	for _, extmap := range result {
+		if extmap.ID >= len(used) {
+			for free < len(used) && (used[free] || !validExtmapID(free)) {
+				free++
+			}
+
+			if free == len(used) {
+				continue
+			}
+
+			extmap.ID, used[free] = free, true
+		}
+
+		kept = append(kept, extmap)
+	}
+
+	return kept
+}
+
+// RTCP is the value of the rtcp attribute, following the grammar:
+//
+//	rtcp-attribute = "a=rtcp:" port [nettype space addrtype space connection-address]
+//
+// See RFC 3605 2.1.
+type RTCP struct {
+	Port int
+	// NetType, AddrType and Address are empty, if they were omitted
+	NetType  NetType
+	AddrType AddrType
+	Address  string
+}
+
+func (r RTCP) Parse(value string) (RTCP, error) {
+	port, value, found := strings.Cut(value, " ")
+
+	var err error
+	if r.Port, err = parsePort(port); err != nil {
+		return r, err
+	}
+
+	if !found {
+		return r, nil
+	}
+
+	info, err := ConnectionInfo{}.Parse(value)
+	if err != nil {
+		return r, err
+	}
+
+	r.NetType, r.AddrType, r.Address = info.NetType, info.AddrType, info.Address
+
+	return r, nil
+}
+
+func (r RTCP) AppendTo(buff []byte) []byte {
+	buff = strconv.AppendInt(buff, int64(r.Port), 10)
+
+	if len(r.Address) > 0 {
+		buff = append(buff, ' ')
+		buff = append(buff, r.NetType...)
+		buff = append(buff, ' ')
+		buff = append(buff, r.AddrType...)
+		buff = append(buff, ' ')
+		buff = append(buff, r.Address...)
+	}
+
+	return buff
+}
+
+// RTCP returns the rtcp attribute of the media. If it's absent, zero value is
+// returned, and RTCP uses the next port after the RTP one (or the same port, if
+// rtcp-mux is negotiated)
+func (m Media) RTCP() (RTCP, error) {
+	value, found := m.Attribute("rtcp")
+	if !found {
+		return RTCP{}, nil
+	}
+
+	return RTCP{}.Parse(value)
+}
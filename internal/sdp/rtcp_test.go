@@ -0,0 +1,108 @@
+package sdp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRTCP(t *testing.T) {
+	desc, err := NewParser().Parse([]byte("v=0\r\n" +
+		"o=- 0 0 IN IP4 127.0.0.1\r\n" +
+		"s=-\r\n" +
+		"t=0 0\r\n" +
+		"a=extmap:1 urn:ietf:params:rtp-hdrext:sdes:mid\r\n" +
+		"m=video 49170 RTP/AVPF 96 97\r\n" +
+		"a=rtpmap:96 VP8/90000\r\n" +
+		"a=rtpmap:97 H264/90000\r\n" +
+		"a=rtcp:53020 IN IP4 126.16.64.4\r\n" +
+		"a=rtcp-fb:* nack\r\n" +
+		"a=rtcp-fb:96 nack pli\r\n" +
+		"a=rtcp-fb:96 ccm fir\r\n" +
+		"a=rtcp-fb:97 goog-remb\r\n" +
+		"a=rtcp-fb:97 transport-cc\r\n" +
+		"a=extmap:2/sendonly urn:ietf:params:rtp-hdrext:toffset\r\n" +
+		"a=extmap:3 urn:3gpp:video-orientation attr\r\n"))
+	require.NoError(t, err)
+	media := desc.Media[0]
+
+	t.Run("feedback", func(t *testing.T) {
+		feedback, err := media.FeedbackFor(96)
+		require.NoError(t, err)
+		require.Equal(t, []RTCPFeedback{
+			{Any: true, Type: "nack"},
+			{PayloadType: 96, Type: "nack", Param: "pli"},
+			{PayloadType: 96, Type: "ccm", Param: "fir"},
+		}, feedback)
+		require.Equal(t, "* nack", string(feedback[0].AppendTo(nil)))
+		require.Equal(t, "96 ccm fir", string(feedback[2].AppendTo(nil)))
+
+		offered, err := media.RTCPFeedback()
+		require.NoError(t, err)
+		negotiated := NegotiateFeedback(offered, []RTCPFeedback{
+			{Type: "nack", Param: "pli"}, {Type: "transport-cc"},
+		})
+		require.Equal(t, []RTCPFeedback{
+			{PayloadType: 96, Type: "nack", Param: "pli"},
+			{PayloadType: 97, Type: "transport-cc"},
+		}, negotiated)
+
+		_, err = RTCPFeedback{}.Parse("96")
+		require.Equal(t, ErrBadSyntax, err)
+	})
+
+	t.Run("extmap", func(t *testing.T) {
+		extmaps, err := desc.Extmaps(0)
+		require.NoError(t, err)
+		require.Equal(t, []Extmap{
+			{ID: 1, URI: "urn:ietf:params:rtp-hdrext:sdes:mid"},
+			{ID: 2, Direction: SendOnly, URI: "urn:ietf:params:rtp-hdrext:toffset"},
+			{ID: 3, URI: "urn:3gpp:video-orientation", Attributes: "attr"},
+		}, extmaps)
+		require.Equal(t, "2/sendonly urn:ietf:params:rtp-hdrext:toffset", string(extmaps[1].AppendTo(nil)))
+
+		answered := NegotiateExtmaps(extmaps, []string{"urn:ietf:params:rtp-hdrext:toffset"})
+		require.Equal(t, []Extmap{
+			{ID: 2, Direction: RecvOnly, URI: "urn:ietf:params:rtp-hdrext:toffset"},
+		}, answered)
+
+		answered = NegotiateExtmaps([]Extmap{
+			{ID: 4096, URI: "urn:a"},
+			{ID: 1, URI: "urn:b"},
+			{ID: 4351, Direction: SendOnly, URI: "urn:c"},
+			{ID: 4097, URI: "urn:unsupported"},
+		}, []string{"urn:a", "urn:b", "urn:c"})
+		require.Equal(t, []Extmap{
+			{ID: 2, URI: "urn:a"},
+			{ID: 1, URI: "urn:b"},
+			{ID: 3, Direction: RecvOnly, URI: "urn:c"},
+		}, answered)
+
+		// 1-14 and 16-255 are taken already
+		var crowded []Extmap
+		for id := 1; id <= 255; id++ {
+			if id != 15 {
+				crowded = append(crowded, Extmap{ID: id, URI: "urn:x"})
+			}
+		}
+		answered = NegotiateExtmaps(append(crowded, Extmap{ID: 4096, URI: "urn:x"}), []string{"urn:x"})
+		require.Len(t, answered, len(crowded))
+		require.Equal(t, 255, answered[len(answered)-1].ID)
+
+		for _, value := range []string{"15 urn:x", "0 urn:x", "1/both urn:x", "1"} {
+			_, err := Extmap{}.Parse(value)
+			require.Equal(t, ErrBadSyntax, err, value)
+		}
+	})
+
+	t.Run("rtcp attribute", func(t *testing.T) {
+		rtcp, err := media.RTCP()
+		require.NoError(t, err)
+		require.Equal(t, RTCP{Port: 53020, NetType: IN, AddrType: IP4, Address: "126.16.64.4"}, rtcp)
+		require.Equal(t, "53020 IN IP4 126.16.64.4", string(rtcp.AppendTo(nil)))
+
+		rtcp, err = RTCP{}.Parse("53020")
+		require.NoError(t, err)
+		require.Equal(t, "53020", string(rtcp.AppendTo(nil)))
+	})
+}
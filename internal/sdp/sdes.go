@@ -0,0 +1,285 @@
+package sdp
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// CryptoSuite is the SRTP crypto suite. See RFC 4568 6.2 and RFC 7714 14.2
+type CryptoSuite string
+
+const (
+	AESCM128HMACSHA180 CryptoSuite = "AES_CM_128_HMAC_SHA1_80"
+	AESCM128HMACSHA132 CryptoSuite = "AES_CM_128_HMAC_SHA1_32"
+	AES192CMHMACSHA180 CryptoSuite = "AES_192_CM_HMAC_SHA1_80"
+	AES192CMHMACSHA132 CryptoSuite = "AES_192_CM_HMAC_SHA1_32"
+	AES256CMHMACSHA180 CryptoSuite = "AES_256_CM_HMAC_SHA1_80"
+	AES256CMHMACSHA132 CryptoSuite = "AES_256_CM_HMAC_SHA1_32"
+	AEADAES128GCM      CryptoSuite = "AEAD_AES_128_GCM"
+	AEADAES256GCM      CryptoSuite = "AEAD_AES_256_GCM"
+)
+
+// KeyLength returns the length of the concatenated master key and salt in bytes.
+// Zero is returned for unknown suites
+func (c CryptoSuite) KeyLength() int {
+	switch c {
+	case AESCM128HMACSHA180, AESCM128HMACSHA132:
+		return 16 + 14
+	case AES192CMHMACSHA180, AES192CMHMACSHA132:
+		return 24 + 14
+	case AES256CMHMACSHA180, AES256CMHMACSHA132:
+		return 32 + 14
+	case AEADAES128GCM:
+		return 16 + 12
+	case AEADAES256GCM:
+		return 32 + 12
+	default:
+		return 0
+	}
+}
+
+// CryptoKey is a single key parameter of the inline key method, following the grammar:
+//
+//	key-info = key-salt ["|" lifetime] ["|" mki]
+//	lifetime = ["2^"] 1*(DIGIT)
+//	mki      = mki-value ":" mki-length
+type CryptoKey struct {
+	// KeySalt is the concatenated master key and salt
+	KeySalt []byte
+	// Lifetime is the number of packets the key may be used for. Zero means it was
+	// omitted. Powers of two are written in the 2^n form
+	Lifetime uint64
+	MKI      uint64
+	// MKILength is the length of the MKI field in bytes. Zero means MKI was omitted
+	MKILength int
+}
+
+func (c CryptoKey) Parse(value string) (CryptoKey, error) {
+	if !strings.HasPrefix(value, "inline:") {
+		// RFC 4568 defines no other key method
+		return c, ErrBadSyntax
+	}
+
+	value = value[len("inline:"):]
+	keySalt, rest, _ := strings.Cut(value, "|")
+
+	var err error
+	if c.KeySalt, err = base64.StdEncoding.DecodeString(keySalt); err != nil {
+		if c.KeySalt, err = base64.RawStdEncoding.DecodeString(keySalt); err != nil {
+			return c, ErrBadSyntax
+		}
+	}
+
+	if len(rest) == 0 {
+		return c, nil
+	}
+
+	field, rest, _ := strings.Cut(rest, "|")
+	if !strings.Contains(field, ":") {
+		if c.Lifetime, err = parseLifetime(field); err != nil {
+			return c, err
+		}
+
+		field, rest, _ = strings.Cut(rest, "|")
+	}
+
+	if len(rest) > 0 {
+		return c, ErrBadSyntax
+	}
+
+	if len(field) == 0 {
+		return c, nil
+	}
+
+	mki, length, found := strings.Cut(field, ":")
+	if !found {
+		return c, ErrBadSyntax
+	}
+
+	if c.MKI, err = strconv.ParseUint(mki, 10, 64); err != nil {
+		return c, ErrBadSyntax
+	}
+
+	if c.MKILength, err = strconv.Atoi(length); err != nil || c.MKILength < 1 || c.MKILength > 128 {
+		return c, ErrBadSyntax
+	}
+
+	return c, nil
+}
+
+func (c CryptoKey) AppendTo(buff []byte) []byte {
+	buff = append(buff, "inline:"...)
+	buff = append(buff, base64.StdEncoding.EncodeToString(c.KeySalt)...)
+
+	if c.Lifetime > 0 {
+		buff = append(buff, '|')
+		if c.Lifetime&(c.Lifetime-1) == 0 {
+			buff = append(buff, "2^"...)
+			buff = strconv.AppendInt(buff, int64(bits.TrailingZeros64(c.Lifetime)), 10)
+		} else {
+			buff = strconv.AppendUint(buff, c.Lifetime, 10)
+		}
+	}
+
+	if c.MKILength > 0 {
+		buff = append(buff, '|')
+		buff = strconv.AppendUint(buff, c.MKI, 10)
+		buff = append(buff, ':')
+		buff = strconv.AppendInt(buff, int64(c.MKILength), 10)
+	}
+
+	return buff
+}
+
+func parseLifetime(value string) (uint64, error) {
+	if strings.HasPrefix(value, "2^") {
+		n, err := strconv.ParseUint(value[len("2^"):], 10, 8)
+		if err != nil || n > 63 {
+			return 0, ErrBadSyntax
+		}
+
+		return 1 << n, nil
+	}
+
+	lifetime, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || lifetime == 0 {
+		return 0, ErrBadSyntax
+	}
+
+	return lifetime, nil
+}
+
+// Crypto is the value of the crypto attribute, following the grammar:
+//
+//	crypto-attribute = "crypto:" tag 1*WSP crypto-suite 1*WSP key-params
+//	                   *(1*WSP session-param)
+//	key-params       = key-param *(";" key-param)
+//
+// See RFC 4568 9.1.
+type Crypto struct {
+	Tag   int
+	Suite CryptoSuite
+	Keys  []CryptoKey
+	// SessionParams are kept as is, e.g. UNENCRYPTED_SRTCP or KDR=24
+	SessionParams []string
+}
+
+func (c Crypto) Parse(value string) (Crypto, error) {
+	fields := strings.Fields(value)
+	if len(fields) < 3 {
+		return c, ErrBadSyntax
+	}
+
+	var err error
+	if c.Tag, err = strconv.Atoi(fields[0]); err != nil || c.Tag < 0 || c.Tag > 999999999 {
+		return c, ErrBadSyntax
+	}
+
+	c.Suite = CryptoSuite(fields[1])
+	keyParams := strings.Split(fields[2], ";")
+	c.Keys = make([]CryptoKey, len(keyParams))
+
+	for i, param := range keyParams {
+		if c.Keys[i], err = (CryptoKey{}).Parse(param); err != nil {
+			return c, err
+		}
+
+		if length := c.Suite.KeyLength(); length > 0 && len(c.Keys[i].KeySalt) != length {
+			return c, ErrBadSyntax
+		}
+	}
+
+	if len(fields) > 3 {
+		c.SessionParams = fields[3:]
+	}
+
+	return c, nil
+}
+
+func (c Crypto) AppendTo(buff []byte) []byte {
+	buff = strconv.AppendInt(buff, int64(c.Tag), 10)
+	buff = append(buff, ' ')
+	buff = append(buff, c.Suite...)
+	buff = append(buff, ' ')
+
+	for i, key := range c.Keys {
+		if i > 0 {
+			buff = append(buff, ';')
+		}
+
+		buff = key.AppendTo(buff)
+	}
+
+	for _, param := range c.SessionParams {
+		buff = append(buff, ' ')
+		buff = append(buff, param...)
+	}
+
+	return buff
+}
+
+// NewCrypto creates the crypto attribute with a freshly generated key
+func NewCrypto(tag int, suite CryptoSuite) (Crypto, error) {
+	length := suite.KeyLength()
+	if length == 0 {
+		return Crypto{}, ErrUnknownCryptoSuite
+	}
+
+	key := make([]byte, length)
+	if _, err := rand.Read(key); err != nil {
+		return Crypto{}, err
+	}
+
+	return Crypto{
+		Tag:   tag,
+		Suite: suite,
+		Keys:  []CryptoKey{{KeySalt: key}},
+	}, nil
+}
+
+// Cryptos returns the crypto attributes of the media in the order of preference
+func (m Media) Cryptos() ([]Crypto, error) {
+	var cryptos []Crypto
+
+	for _, attr := range m.Attributes {
+		if attr.Key != "crypto" {
+			continue
+		}
+
+		crypto, err := Crypto{}.Parse(attr.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		cryptos = append(cryptos, crypto)
+	}
+
+	return cryptos, nil
+}
+
+// NegotiateCrypto picks the first offered crypto attribute using one of the suites
+// and generates the local one for the answer, sharing its tag and suite. Attributes
+// with session parameters are skipped, as none of them is supported, and RFC 4568
+// 6.3 forbids accepting unknown ones
+func NegotiateCrypto(offered []Crypto, suites []CryptoSuite) (remote, local Crypto, err error) {
+	for _, crypto := range offered {
+		if len(crypto.SessionParams) > 0 {
+			continue
+		}
+
+		for _, suite := range suites {
+			if crypto.Suite != suite {
+				continue
+			}
+
+			local, err = NewCrypto(crypto.Tag, suite)
+
+			return crypto, local, err
+		}
+	}
+
+	return remote, local, ErrNoCommonCryptoSuite
+}
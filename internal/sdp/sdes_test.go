@@ -0,0 +1,66 @@
+package sdp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrypto(t *testing.T) {
+	t.Run("parse and serialize", func(t *testing.T) {
+		for _, value := range []string{
+			"1 AES_CM_128_HMAC_SHA1_80 inline:PS1uQCVeeCFCanVmcjkpPywjNWhcYD0mXXtxaVBR|2^20|1:32",
+			"2 AES_CM_128_HMAC_SHA1_32 inline:NzB4d1BINUAvLEw6UzF3WSJ+PSdFcGdUJShpX1Zj",
+			"3 AES_256_CM_HMAC_SHA1_80 inline:xvFxvG7tujR/1SDe1S4G11da88m0q/DpNJNoXMLFAJXJAZ1HjcfLtnrnW4TLCQ==|1000 UNENCRYPTED_SRTCP",
+			"4 AEAD_AES_128_GCM inline:88B8ZckE4Yo/h6jycETtcMKUf7ffz5onZuqFcw==|2^31|5:4;inline:Di0pw2kwljYSIiys/F9OpANfIC0xAmHdkFXhQg==|2^31|6:4",
+		} {
+			crypto, err := Crypto{}.Parse(value)
+			require.NoError(t, err, value)
+			require.Equal(t, value, string(crypto.AppendTo(nil)))
+		}
+
+		crypto, err := Crypto{}.Parse("1 AES_CM_128_HMAC_SHA1_80 inline:PS1uQCVeeCFCanVmcjkpPywjNWhcYD0mXXtxaVBR|2^20|1:32")
+		require.NoError(t, err)
+		require.Equal(t, 1, crypto.Tag)
+		require.Len(t, crypto.Keys, 1)
+		require.Len(t, crypto.Keys[0].KeySalt, 30)
+		require.Equal(t, uint64(1<<20), crypto.Keys[0].Lifetime)
+		require.Equal(t, uint64(1), crypto.Keys[0].MKI)
+		require.Equal(t, 32, crypto.Keys[0].MKILength)
+
+		for _, value := range []string{
+			"1 AES_CM_128_HMAC_SHA1_80",
+			"1 AES_CM_128_HMAC_SHA1_80 uri:PS1uQCVeeCFCanVmcjkpPywjNWhcYD0mXXtxaVBR",
+			"1 AES_CM_128_HMAC_SHA1_80 inline:NzB4d1BINUAvLEw6UzF3WSJ+PSdF",
+			"1 AES_CM_128_HMAC_SHA1_80 inline:PS1uQCVeeCFCanVmcjkpPywjNWhcYD0mXXtxaVBR|2^20|1:32|x",
+			"x AES_CM_128_HMAC_SHA1_80 inline:PS1uQCVeeCFCanVmcjkpPywjNWhcYD0mXXtxaVBR",
+		} {
+			_, err := Crypto{}.Parse(value)
+			require.Equal(t, ErrBadSyntax, err, value)
+		}
+	})
+
+	t.Run("negotiation", func(t *testing.T) {
+		media := Media{Type: Audio, Port: 49170, Proto: RTPSAVP, Formats: []string{"0"}, Attributes: []Attribute{
+			{Key: "crypto", Value: "1 AES_256_CM_HMAC_SHA1_80 inline:xvFxvG7tujR/1SDe1S4G11da88m0q/DpNJNoXMLFAJXJAZ1HjcfLtnrnW4TLCQ=="},
+			{Key: "crypto", Value: "2 AES_CM_128_HMAC_SHA1_80 inline:NzB4d1BINUAvLEw6UzF3WSJ+PSdFcGdUJShpX1Zj KDR=1"},
+			{Key: "crypto", Value: "3 AES_CM_128_HMAC_SHA1_80 inline:PS1uQCVeeCFCanVmcjkpPywjNWhcYD0mXXtxaVBR"},
+		}}
+		offered, err := media.Cryptos()
+		require.NoError(t, err)
+		require.Len(t, offered, 3)
+
+		remote, local, err := NegotiateCrypto(offered, []CryptoSuite{AESCM128HMACSHA180, AESCM128HMACSHA132})
+		require.NoError(t, err)
+		require.Equal(t, 3, remote.Tag, "crypto with unknown session params must be skipped")
+		require.Equal(t, 3, local.Tag)
+		require.Equal(t, AESCM128HMACSHA180, local.Suite)
+		require.Len(t, local.Keys[0].KeySalt, 30)
+		require.NotEqual(t, remote.Keys[0].KeySalt, local.Keys[0].KeySalt)
+
+		_, _, err = NegotiateCrypto(offered, []CryptoSuite{AEADAES128GCM})
+		require.Equal(t, ErrNoCommonCryptoSuite, err)
+		_, err = NewCrypto(1, "NULL_HMAC_SHA1_80")
+		require.Equal(t, ErrUnknownCryptoSuite, err)
+	})
+}
@@ -0,0 +1,403 @@
+package sdp
+
+import (
+	"strconv"
+	"strings"
+)
+
+// StreamDirection is the direction of RTP streams in the rid and simulcast attributes
+type StreamDirection string
+
+const (
+	Send StreamDirection = "send"
+	Recv StreamDirection = "recv"
+)
+
+// Reverse returns the direction as it's seen by the other party
+func (s StreamDirection) Reverse() StreamDirection {
+	if s == Send {
+		return Recv
+	}
+
+	return Send
+}
+
+// RIDParam is a single restriction of the rid attribute, e.g. max-width=1280.
+// Value is empty for parameters without one
+type RIDParam struct {
+	Key   string
+	Value string
+}
+
+// RID is the value of the rid attribute, following the grammar:
+//
+//	rid-syntax        = "a=rid:" rid-id SP rid-dir [ rid-pt-param-list / rid-param-list ]
+//	rid-pt-param-list = SP rid-fmt-list *( ";" rid-param )
+//	rid-fmt-list      = "pt=" fmt *( "," fmt )
+//
+// See RFC 8851 10.
+type RID struct {
+	ID        string
+	Direction StreamDirection
+	// Formats are the payload types the stream is restricted to. Empty means any
+	Formats []string
+	Params  []RIDParam
+}
+
+func (r RID) Parse(value string) (RID, error) {
+	fields := strings.Fields(value)
+	if len(fields) < 2 || len(fields) > 3 || !validRIDID(fields[0]) {
+		return r, ErrBadSyntax
+	}
+
+	r.ID, r.Direction = fields[0], StreamDirection(fields[1])
+	if r.Direction != Send && r.Direction != Recv {
+		return r, ErrBadSyntax
+	}
+
+	if len(fields) == 2 {
+		return r, nil
+	}
+
+	for i, param := range strings.Split(fields[2], ";") {
+		key, value, _ := strings.Cut(param, "=")
+		if len(key) == 0 {
+			return r, ErrBadSyntax
+		}
+
+		if key == "pt" {
+			if i != 0 || len(value) == 0 {
+				// the format list must come first. See RFC 8851 10
+				return r, ErrBadSyntax
+			}
+
+			r.Formats = strings.Split(value, ",")
+			continue
+		}
+
+		r.Params = append(r.Params, RIDParam{Key: key, Value: value})
+	}
+
+	return r, nil
+}
+
+func (r RID) AppendTo(buff []byte) []byte {
+	buff = append(buff, r.ID...)
+	buff = append(buff, ' ')
+	buff = append(buff, r.Direction...)
+
+	if len(r.Formats) == 0 && len(r.Params) == 0 {
+		return buff
+	}
+
+	buff = append(buff, ' ')
+
+	if len(r.Formats) > 0 {
+		buff = append(buff, "pt="...)
+		buff = append(buff, strings.Join(r.Formats, ",")...)
+	}
+
+	for i, param := range r.Params {
+		if i > 0 || len(r.Formats) > 0 {
+			buff = append(buff, ';')
+		}
+
+		buff = append(buff, param.Key...)
+		if len(param.Value) > 0 {
+			buff = append(buff, '=')
+			buff = append(buff, param.Value...)
+		}
+	}
+
+	return buff
+}
+
+// Param returns the value of the restriction
+func (r RID) Param(key string) (value string, found bool) {
+	for _, param := range r.Params {
+		if param.Key == key {
+			return param.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// MaxWidth returns the max-width restriction in pixels
+func (r RID) MaxWidth() (int, bool) {
+	return r.intParam("max-width")
+}
+
+// MaxHeight returns the max-height restriction in pixels
+func (r RID) MaxHeight() (int, bool) {
+	return r.intParam("max-height")
+}
+
+// MaxFPS returns the max-fps restriction in frames per second
+func (r RID) MaxFPS() (float64, bool) {
+	value, found := r.Param("max-fps")
+	if !found {
+		return 0, false
+	}
+
+	fps, err := strconv.ParseFloat(value, 64)
+
+	return fps, err == nil
+}
+
+func (r RID) intParam(key string) (int, bool) {
+	value, found := r.Param(key)
+	if !found {
+		return 0, false
+	}
+
+	n, err := strconv.Atoi(value)
+
+	return n, err == nil
+}
+
+// Answer returns the rid as the answerer sees it: the direction is reversed, and
+// the payload types are restricted to the answered formats. False is returned, if
+// none of the restricted payload types was answered, so the rid must be removed.
+// See RFC 8851 7.2
+func (r RID) Answer(formats []string) (RID, bool) {
+	r.Direction = r.Direction.Reverse()
+
+	if len(r.Formats) == 0 {
+		return r, true
+	}
+
+	accepted := make([]string, 0, len(r.Formats))
+	for _, format := range r.Formats {
+		for _, answered := range formats {
+			if format == answered {
+				accepted = append(accepted, format)
+				break
+			}
+		}
+	}
+
+	r.Formats = accepted
+
+	return r, len(accepted) > 0
+}
+
+func validRIDID(id string) bool {
+	if len(id) == 0 {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		switch c := id[i]; {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// RIDs returns the rid attributes of the media
+func (m Media) RIDs() ([]RID, error) {
+	var rids []RID
+
+	for _, attr := range m.Attributes {
+		if attr.Key != "rid" {
+			continue
+		}
+
+		rid, err := RID{}.Parse(attr.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		rids = append(rids, rid)
+	}
+
+	return rids, nil
+}
+
+// SimulcastID refers to a single rid within the simulcast attribute
+type SimulcastID struct {
+	RID    string
+	Paused bool
+}
+
+// SimulcastStream is a single simulcast stream, described by the alternative rids
+// in the order of preference
+type SimulcastStream []SimulcastID
+
+// Simulcast is the value of the simulcast attribute, following the grammar:
+//
+//	sc-value     = ( sc-send [SP sc-recv] ) / ( sc-recv [SP sc-send] )
+//	sc-str-list  = sc-alt-list *( ";" sc-alt-list )
+//	sc-alt-list  = sc-id *( "," sc-id )
+//	sc-id        = [sc-id-paused] rid-id
+//
+// See RFC 8853 5.1.
+type Simulcast struct {
+	Send []SimulcastStream
+	Recv []SimulcastStream
+	// RecvFirst keeps the order of directions as received
+	RecvFirst bool
+}
+
+func (s Simulcast) Parse(value string) (Simulcast, error) {
+	fields := strings.Fields(value)
+	if len(fields) != 2 && len(fields) != 4 {
+		return s, ErrBadSyntax
+	}
+
+	for i := 0; i < len(fields); i += 2 {
+		streams, err := parseSimulcastStreams(fields[i+1])
+		if err != nil {
+			return s, err
+		}
+
+		switch StreamDirection(fields[i]) {
+		case Send:
+			if s.Send != nil {
+				return s, ErrBadSyntax
+			}
+
+			s.Send = streams
+		case Recv:
+			if s.Recv != nil {
+				return s, ErrBadSyntax
+			}
+
+			s.Recv, s.RecvFirst = streams, i == 0
+		default:
+			return s, ErrBadSyntax
+		}
+	}
+
+	return s, nil
+}
+
+func parseSimulcastStreams(value string) ([]SimulcastStream, error) {
+	alternatives := strings.Split(value, ";")
+	streams := make([]SimulcastStream, len(alternatives))
+
+	for i, alternative := range alternatives {
+		ids := strings.Split(alternative, ",")
+		streams[i] = make(SimulcastStream, len(ids))
+
+		for j, id := range ids {
+			if len(id) > 0 && id[0] == '~' {
+				streams[i][j].Paused, id = true, id[1:]
+			}
+
+			if !validRIDID(id) {
+				return nil, ErrBadSyntax
+			}
+
+			streams[i][j].RID = id
+		}
+	}
+
+	return streams, nil
+}
+
+func (s Simulcast) AppendTo(buff []byte) []byte {
+	first, second := Send, Recv
+	if s.RecvFirst {
+		first, second = Recv, Send
+	}
+
+	for _, dir := range [2]StreamDirection{first, second} {
+		streams := s.streams(dir)
+		if len(streams) == 0 {
+			continue
+		}
+
+		if dir == second && len(s.streams(first)) > 0 {
+			buff = append(buff, ' ')
+		}
+
+		buff = append(buff, dir...)
+		buff = append(buff, ' ')
+		buff = appendSimulcastStreams(buff, streams)
+	}
+
+	return buff
+}
+
+func (s Simulcast) streams(dir StreamDirection) []SimulcastStream {
+	if dir == Send {
+		return s.Send
+	}
+
+	return s.Recv
+}
+
+func appendSimulcastStreams(buff []byte, streams []SimulcastStream) []byte {
+	for i, stream := range streams {
+		if i > 0 {
+			buff = append(buff, ';')
+		}
+
+		for j, id := range stream {
+			if j > 0 {
+				buff = append(buff, ',')
+			}
+
+			if id.Paused {
+				buff = append(buff, '~')
+			}
+
+			buff = append(buff, id.RID...)
+		}
+	}
+
+	return buff
+}
+
+// Answer returns the simulcast attribute as the answerer sees it: directions are
+// swapped and only the accepted rids are kept, so layers may be reduced. Streams
+// without accepted alternatives are removed. False is returned, if nothing is left.
+// See RFC 8853 5.3
+func (s Simulcast) Answer(accepted []string) (Simulcast, bool) {
+	answer := Simulcast{
+		Send:      filterSimulcastStreams(s.Recv, accepted),
+		Recv:      filterSimulcastStreams(s.Send, accepted),
+		RecvFirst: !s.RecvFirst,
+	}
+
+	return answer, len(answer.Send) > 0 || len(answer.Recv) > 0
+}
+
+func filterSimulcastStreams(streams []SimulcastStream, accepted []string) []SimulcastStream {
+	var result []SimulcastStream
+
+	for _, stream := range streams {
+		var filtered SimulcastStream
+
+		for _, id := range stream {
+			for _, rid := range accepted {
+				if id.RID == rid {
+					filtered = append(filtered, id)
+					break
+				}
+			}
+		}
+
+		if len(filtered) > 0 {
+			result = append(result, filtered)
+		}
+	}
+
+	return result
+}
+
+// Simulcast returns the simulcast attribute of the media. Zero value is returned,
+// if it's absent
+func (m Media) Simulcast() (Simulcast, error) {
+	value, found := m.Attribute("simulcast")
+	if !found {
+		return Simulcast{}, nil
+	}
+
+	return Simulcast{}.Parse(value)
+}
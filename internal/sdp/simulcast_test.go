@@ -0,0 +1,78 @@
+package sdp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulcast(t *testing.T) {
+	media := Media{Type: Video, Port: 9, Proto: UDPTLSRTPSAVPF, Formats: []string{"96", "97"}, Attributes: []Attribute{
+		{Key: "rid", Value: "f send pt=96,97;max-width=1280;max-height=720;max-fps=30"},
+		{Key: "rid", Value: "h send pt=97;max-width=640"},
+		{Key: "rid", Value: "q send max-br=150000"},
+		{Key: "simulcast", Value: "send f;h,~q"},
+	}}
+
+	t.Run("rid", func(t *testing.T) {
+		rids, err := media.RIDs()
+		require.NoError(t, err)
+		require.Len(t, rids, 3)
+		require.Equal(t, RID{
+			ID: "f", Direction: Send, Formats: []string{"96", "97"},
+			Params: []RIDParam{{"max-width", "1280"}, {"max-height", "720"}, {"max-fps", "30"}},
+		}, rids[0])
+
+		width, found := rids[0].MaxWidth()
+		require.True(t, found)
+		require.Equal(t, 1280, width)
+		fps, found := rids[0].MaxFPS()
+		require.True(t, found)
+		require.Equal(t, 30.0, fps)
+		_, found = rids[2].MaxHeight()
+		require.False(t, found)
+
+		for i, attr := range media.Attributes[:3] {
+			require.Equal(t, attr.Value, string(rids[i].AppendTo(nil)))
+		}
+
+		answered, ok := rids[0].Answer([]string{"96"})
+		require.True(t, ok)
+		require.Equal(t, Recv, answered.Direction)
+		require.Equal(t, []string{"96"}, answered.Formats)
+		_, ok = rids[1].Answer([]string{"96"})
+		require.False(t, ok)
+		_, ok = rids[2].Answer([]string{"96"})
+		require.True(t, ok)
+
+		for _, value := range []string{"f", "f both", "f send max-width=1;pt=96", "f! send"} {
+			_, err := RID{}.Parse(value)
+			require.Equal(t, ErrBadSyntax, err, value)
+		}
+	})
+
+	t.Run("simulcast", func(t *testing.T) {
+		simulcast, err := media.Simulcast()
+		require.NoError(t, err)
+		require.Equal(t, Simulcast{Send: []SimulcastStream{
+			{{RID: "f"}},
+			{{RID: "h"}, {RID: "q", Paused: true}},
+		}}, simulcast)
+		require.Equal(t, "send f;h,~q", string(simulcast.AppendTo(nil)))
+
+		answer, ok := simulcast.Answer([]string{"f", "q"})
+		require.True(t, ok)
+		require.Equal(t, "recv f;~q", string(answer.AppendTo(nil)))
+		_, ok = simulcast.Answer([]string{"x"})
+		require.False(t, ok)
+
+		both, err := Simulcast{}.Parse("recv 1;2 send 3")
+		require.NoError(t, err)
+		require.Equal(t, "recv 1;2 send 3", string(both.AppendTo(nil)))
+
+		for _, value := range []string{"send", "send f recv", "send f send h", "both f", "send f;;h"} {
+			_, err := Simulcast{}.Parse(value)
+			require.Equal(t, ErrBadSyntax, err, value)
+		}
+	})
+}
@@ -0,0 +1,134 @@
+package sdp
+
+import "strconv"
+
+// SyntaxError is returned by the strict parser. It carries the 1-based number of
+// the line the error was found at
+type SyntaxError struct {
+	Line int
+	Err  error
+}
+
+func (s SyntaxError) Error() string {
+	return "line " + strconv.Itoa(s.Line) + ": " + s.Err.Error()
+}
+
+func (s SyntaxError) Unwrap() error {
+	return s.Err
+}
+
+// fieldRule describes the position of the field within the description. Fields must
+// come in the order of non-decreasing ranks, and single ones must not be repeated
+type fieldRule struct {
+	rank  int
+	multi bool
+}
+
+// sessionRules and mediaRules follow the order of RFC 8866 5. Repeat times and zone
+// adjustments share the rank with the time description, as they belong to it and
+// time descriptions may be repeated
+var (
+	sessionRules = map[byte]fieldRule{
+		'v': {0, false},
+		'o': {1, false},
+		's': {2, false},
+		'i': {3, false},
+		'u': {4, false},
+		'e': {5, true},
+		'p': {6, true},
+		'c': {7, false},
+		'b': {8, true},
+		't': {9, true},
+		'r': {9, true},
+		'z': {9, true},
+		'k': {11, false},
+		'a': {12, true},
+	}
+	mediaRules = map[byte]fieldRule{
+		'm': {0, false},
+		'i': {1, false},
+		'c': {2, true},
+		'b': {3, true},
+		'k': {4, false},
+		'a': {5, true},
+	}
+)
+
+// orderChecker validates the order and the cardinality of fields
+type orderChecker struct {
+	rules map[byte]fieldRule
+	rank  int
+	seen  [256]bool
+	prev  byte
+	// sessionConn is set if c= is present at the session level. Otherwise, each
+	// media must have its own. mediaLine is the line the current media starts at
+	sessionConn bool
+	mediaConn   bool
+	mediaLine   int
+}
+
+func newOrderChecker() orderChecker {
+	return orderChecker{rules: sessionRules}
+}
+
+func (o *orderChecker) check(key byte, line int) error {
+	if key == 'm' {
+		if err := o.endSection(); err != nil {
+			return err
+		}
+
+		o.rules, o.rank, o.prev, o.mediaConn, o.mediaLine = mediaRules, 0, 0, false, line
+		o.seen = [256]bool{}
+	}
+
+	rule, found := o.rules[key]
+	switch {
+	case !found:
+		return ErrUnrecognizedKey
+	case rule.rank < o.rank:
+		return ErrFieldOrder
+	case !rule.multi && o.seen[key]:
+		return ErrDuplicateField
+	case key == 'r' && o.prev != 't' && o.prev != 'r':
+		return ErrFieldOrder
+	case key == 'z' && o.prev != 'r':
+		// at most a single z= closes the repeat description. See RFC 8866 9
+		return ErrFieldOrder
+	}
+
+	if key == 'c' {
+		if o.mediaLine == 0 {
+			o.sessionConn = true
+		} else {
+			o.mediaConn = true
+		}
+	}
+
+	o.rank, o.prev, o.seen[key] = rule.rank, key, true
+
+	return nil
+}
+
+// finish checks the last section. It must be called after all the lines are consumed
+func (o *orderChecker) finish() error {
+	return o.endSection()
+}
+
+func (o *orderChecker) endSection() error {
+	if o.mediaLine == 0 {
+		for _, key := range []byte("vost") {
+			if !o.seen[key] {
+				return ErrMissingField
+			}
+		}
+
+		return nil
+	}
+
+	if !o.sessionConn && !o.mediaConn {
+		// c= is compulsory unless it's present at the session level. See RFC 8866 5.7
+		return SyntaxError{Line: o.mediaLine, Err: ErrMissingField}
+	}
+
+	return nil
+}
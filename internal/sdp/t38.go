@@ -0,0 +1,235 @@
+package sdp
+
+import (
+	"strconv"
+	"strings"
+)
+
+// T38RateManagement is the value of T38FaxRateManagement
+type T38RateManagement string
+
+const (
+	LocalTCF       T38RateManagement = "localTCF"
+	TransferredTCF T38RateManagement = "transferredTCF"
+)
+
+// T38ErrorCorrection is the value of T38FaxUdpEC. Schemes are listed from the weakest
+// to the strongest one
+type T38ErrorCorrection string
+
+const (
+	T38NoEC       T38ErrorCorrection = "t38UDPNoEC"
+	T38Redundancy T38ErrorCorrection = "t38UDPRedundancy"
+	T38FEC        T38ErrorCorrection = "t38UDPFEC"
+)
+
+func (t T38ErrorCorrection) strength() int {
+	switch t {
+	case T38Redundancy:
+		return 1
+	case T38FEC:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// T38Params are the T.38 fax parameters of the image media, as ITU-T T.38 Annex D
+// defines. Zero values of numeric parameters mean they were omitted, except Version:
+// zero is the version 0, which is also the default one, so it's always written
+type T38Params struct {
+	Version         int
+	MaxBitRate      int
+	FillBitRemoval  bool
+	TranscodingMMR  bool
+	TranscodingJBIG bool
+	RateManagement  T38RateManagement
+	MaxBuffer       int
+	MaxDatagram     int
+	ErrorCorrection T38ErrorCorrection
+}
+
+// IsT38 reports whether the media is the T.38 fax over UDPTL
+func (m Media) IsT38() bool {
+	if m.Type != Image || !strings.EqualFold(string(m.Proto), string(UDPTL)) {
+		return false
+	}
+
+	for _, format := range m.Formats {
+		if strings.EqualFold(format, "t38") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// T38 returns the T.38 parameters of the media. Attribute names and values are matched
+// case insensitively, as many devices send them in lower case. Values are stored in
+// the canonical case
+func (m Media) T38() (params T38Params, err error) {
+	for _, attr := range m.Attributes {
+		switch strings.ToLower(attr.Key) {
+		case "t38faxversion":
+			params.Version, err = parseT38Number(attr.Value)
+		case "t38maxbitrate":
+			params.MaxBitRate, err = parseT38Number(attr.Value)
+		case "t38faxfillbitremoval":
+			params.FillBitRemoval, err = parseT38Flag(attr.Value)
+		case "t38faxtranscodingmmr":
+			params.TranscodingMMR, err = parseT38Flag(attr.Value)
+		case "t38faxtranscodingjbig":
+			params.TranscodingJBIG, err = parseT38Flag(attr.Value)
+		case "t38faxratemanagement":
+			params.RateManagement, err = parseT38RateManagement(attr.Value)
+		case "t38faxmaxbuffer":
+			params.MaxBuffer, err = parseT38Number(attr.Value)
+		case "t38faxmaxdatagram":
+			params.MaxDatagram, err = parseT38Number(attr.Value)
+		case "t38faxudpec":
+			params.ErrorCorrection, err = parseT38ErrorCorrection(attr.Value)
+		}
+
+		if err != nil {
+			return params, err
+		}
+	}
+
+	return params, nil
+}
+
+// Attributes returns the parameters as media attributes. Boolean parameters are
+// written as property attributes, as most implementations expect
+func (t T38Params) Attributes() []Attribute {
+	attrs := []Attribute{{Key: "T38FaxVersion", Value: strconv.Itoa(t.Version)}}
+
+	if t.MaxBitRate > 0 {
+		attrs = append(attrs, Attribute{Key: "T38MaxBitRate", Value: strconv.Itoa(t.MaxBitRate)})
+	}
+
+	for _, flag := range []struct {
+		key string
+		set bool
+	}{
+		{"T38FaxFillBitRemoval", t.FillBitRemoval},
+		{"T38FaxTranscodingMMR", t.TranscodingMMR},
+		{"T38FaxTranscodingJBIG", t.TranscodingJBIG},
+	} {
+		if flag.set {
+			attrs = append(attrs, Attribute{Key: flag.key})
+		}
+	}
+
+	if len(t.RateManagement) > 0 {
+		attrs = append(attrs, Attribute{Key: "T38FaxRateManagement", Value: string(t.RateManagement)})
+	}
+
+	if t.MaxBuffer > 0 {
+		attrs = append(attrs, Attribute{Key: "T38FaxMaxBuffer", Value: strconv.Itoa(t.MaxBuffer)})
+	}
+
+	if t.MaxDatagram > 0 {
+		attrs = append(attrs, Attribute{Key: "T38FaxMaxDatagram", Value: strconv.Itoa(t.MaxDatagram)})
+	}
+
+	if len(t.ErrorCorrection) > 0 {
+		attrs = append(attrs, Attribute{Key: "T38FaxUdpEC", Value: string(t.ErrorCorrection)})
+	}
+
+	return attrs
+}
+
+// Answer negotiates the offered parameters with the local ones. Version (zero
+// included), bit rate and error correction are lowered to the common ones, optional features are kept only if
+// both sides support them, and rate management is echoed. Buffer and datagram sizes
+// are declarative, so the local ones are used
+func (t T38Params) Answer(local T38Params) T38Params {
+	answer := T38Params{
+		Version:         t.Version,
+		MaxBitRate:      t.MaxBitRate,
+		FillBitRemoval:  t.FillBitRemoval && local.FillBitRemoval,
+		TranscodingMMR:  t.TranscodingMMR && local.TranscodingMMR,
+		TranscodingJBIG: t.TranscodingJBIG && local.TranscodingJBIG,
+		RateManagement:  t.RateManagement,
+		MaxBuffer:       local.MaxBuffer,
+		MaxDatagram:     local.MaxDatagram,
+		ErrorCorrection: t.ErrorCorrection,
+	}
+
+	if local.Version < answer.Version {
+		answer.Version = local.Version
+	}
+
+	if local.MaxBitRate > 0 && (answer.MaxBitRate == 0 || local.MaxBitRate < answer.MaxBitRate) {
+		answer.MaxBitRate = local.MaxBitRate
+	}
+
+	if local.ErrorCorrection.strength() < answer.ErrorCorrection.strength() {
+		answer.ErrorCorrection = local.ErrorCorrection
+	}
+
+	return answer
+}
+
+// NewT38Media creates the image media carrying T.38 over UDPTL
+func NewT38Media(port int, params T38Params) Media {
+	return Media{
+		Type:       Image,
+		Port:       port,
+		Proto:      UDPTL,
+		Formats:    []string{"t38"},
+		Attributes: params.Attributes(),
+	}
+}
+
+// SwitchToT38 replaces the i-th media (usually the audio one) with the T.38 image
+// media, as it's done in the re-INVITE when the fax tone is detected. The media-level
+// connection info is kept
+func (d *Description) SwitchToT38(i int, port int, params T38Params) {
+	media := d.mutableMedia(i)
+	t38 := NewT38Media(port, params)
+	t38.ConnectionInfo = media.ConnectionInfo
+	*media = t38
+}
+
+func parseT38RateManagement(value string) (T38RateManagement, error) {
+	for _, known := range []T38RateManagement{LocalTCF, TransferredTCF} {
+		if strings.EqualFold(value, string(known)) {
+			return known, nil
+		}
+	}
+
+	return "", ErrBadSyntax
+}
+
+func parseT38ErrorCorrection(value string) (T38ErrorCorrection, error) {
+	for _, known := range []T38ErrorCorrection{T38NoEC, T38Redundancy, T38FEC} {
+		if strings.EqualFold(value, string(known)) {
+			return known, nil
+		}
+	}
+
+	return "", ErrBadSyntax
+}
+
+func parseT38Number(value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, ErrBadSyntax
+	}
+
+	return n, nil
+}
+
+// parseT38Flag parses boolean parameters, which are either property attributes or
+// have the value of 0 or 1
+func parseT38Flag(value string) (bool, error) {
+	switch value {
+	case "", "1":
+		return true, nil
+	case "0":
+		return false, nil
+	default:
+		return false, ErrBadSyntax
+	}
+}
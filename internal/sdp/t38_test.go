@@ -0,0 +1,124 @@
+package sdp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestT38(t *testing.T) {
+	const offer = "v=0\r\n" +
+		"o=- 1 2 IN IP4 198.51.100.1\r\n" +
+		"s=-\r\n" +
+		"c=IN IP4 198.51.100.1\r\n" +
+		"t=0 0\r\n" +
+		"m=image 4000 udptl t38\r\n" +
+		"a=T38FaxVersion:1\r\n" +
+		"a=T38MaxBitRate:14400\r\n" +
+		"a=T38FaxFillBitRemoval\r\n" +
+		"a=t38faxtranscodingmmr:0\r\n" +
+		"a=T38FaxRateManagement:transferredTCF\r\n" +
+		"a=T38FaxMaxBuffer:262\r\n" +
+		"a=T38FaxMaxDatagram:176\r\n" +
+		"a=T38FaxUdpEC:t38UDPFEC\r\n"
+
+	desc, err := NewParser().Parse([]byte(offer))
+	require.NoError(t, err)
+	require.True(t, desc.Media[0].IsT38())
+
+	params, err := desc.Media[0].T38()
+	require.NoError(t, err)
+	require.Equal(t, T38Params{
+		Version:         1,
+		MaxBitRate:      14400,
+		FillBitRemoval:  true,
+		RateManagement:  TransferredTCF,
+		MaxBuffer:       262,
+		MaxDatagram:     176,
+		ErrorCorrection: T38FEC,
+	}, params)
+
+	t.Run("answer", func(t *testing.T) {
+		answer := params.Answer(T38Params{
+			MaxBitRate:      9600,
+			TranscodingMMR:  true,
+			MaxBuffer:       1024,
+			MaxDatagram:     400,
+			ErrorCorrection: T38Redundancy,
+		})
+		require.Equal(t, T38Params{
+			Version:         0,
+			MaxBitRate:      9600,
+			RateManagement:  TransferredTCF,
+			MaxBuffer:       1024,
+			MaxDatagram:     400,
+			ErrorCorrection: T38Redundancy,
+		}, answer)
+
+		media := NewT38Media(5000, answer)
+		require.Equal(t, "m=image 5000 udptl t38\r\n"+
+			"a=T38FaxVersion:0\r\n"+
+			"a=T38MaxBitRate:9600\r\n"+
+			"a=T38FaxRateManagement:transferredTCF\r\n"+
+			"a=T38FaxMaxBuffer:1024\r\n"+
+			"a=T38FaxMaxDatagram:400\r\n"+
+			"a=T38FaxUdpEC:t38UDPRedundancy\r\n", string(media.AppendTo(nil)))
+
+		reparsed, err := media.T38()
+		require.NoError(t, err)
+		require.Equal(t, answer, reparsed)
+	})
+
+	t.Run("values in another case", func(t *testing.T) {
+		params, err := Media{Attributes: []Attribute{
+			{Key: "t38faxratemanagement", Value: "LOCALTCF"},
+			{Key: "t38faxudpec", Value: "t38udpredundancy"},
+		}}.T38()
+		require.NoError(t, err)
+		require.Equal(t, LocalTCF, params.RateManagement)
+		require.Equal(t, T38Redundancy, params.ErrorCorrection)
+	})
+
+	t.Run("version 0", func(t *testing.T) {
+		// zero is the version itself, not the omitted one
+		answer := params.Answer(T38Params{Version: 0})
+		require.Zero(t, answer.Version)
+		require.Equal(t, Attribute{Key: "T38FaxVersion", Value: "0"}, answer.Attributes()[0])
+
+		answer = T38Params{Version: 0}.Answer(T38Params{Version: 3})
+		require.Zero(t, answer.Version)
+	})
+
+	t.Run("switch from audio", func(t *testing.T) {
+		audio, err := NewParser().Parse([]byte("v=0\r\n" +
+			"o=- 1 1 IN IP4 198.51.100.1\r\n" +
+			"s=-\r\n" +
+			"t=0 0\r\n" +
+			"m=audio 49170 RTP/AVP 0\r\n" +
+			"c=IN IP4 198.51.100.1\r\n" +
+			"a=sendrecv\r\n"))
+		require.NoError(t, err)
+
+		fax := audio
+		fax.SwitchToT38(0, 4000, T38Params{RateManagement: TransferredTCF, ErrorCorrection: T38Redundancy})
+		require.True(t, fax.Media[0].IsT38())
+		require.Equal(t, audio.Media[0].ConnectionInfo, fax.Media[0].ConnectionInfo)
+		require.Equal(t, Audio, audio.Media[0].Type, "original must be kept intact")
+
+		diffs := Diff(audio, fax)
+		require.Len(t, diffs, 1)
+		require.True(t, diffs[0].Changes.Has(PortChanged|CodecsChanged))
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		for _, attr := range []Attribute{
+			{Key: "T38FaxVersion", Value: "x"},
+			{Key: "T38FaxFillBitRemoval", Value: "yes"},
+			{Key: "T38FaxRateManagement", Value: "remoteTCF"},
+			{Key: "T38FaxUdpEC", Value: "t38UDPMagic"},
+		} {
+			_, err := Media{Attributes: []Attribute{attr}}.T38()
+			require.Equal(t, ErrBadSyntax, err, attr)
+		}
+	})
+}
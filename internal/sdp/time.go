@@ -0,0 +1,215 @@
+package sdp
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeDescription is a t= field together with its r= and z= fields. It follows the
+// following grammar (RFC 8866 9):
+//
+//	time-description = time-field [ repeat-description ]
+//	repeat-description = 1*repeat-field [ zone-field ]
+type TimeDescription struct {
+	// Start and Stop are the NTP timestamps (seconds since 1900). Zero Stop means the
+	// session is unbounded, and zero both mean the session is permanent
+	Start uint64
+	Stop  uint64
+	// Repeats are the r= fields, and ZoneAdjustments is the z= field
+	Repeats         []Repeat
+	ZoneAdjustments []ZoneAdjustment
+}
+
+func (t TimeDescription) Parse(value string) (TimeDescription, error) {
+	sp := strings.IndexByte(value, ' ')
+	if sp == -1 {
+		return t, ErrBadSyntax
+	}
+
+	start, err := strconv.ParseUint(value[:sp], 10, 64)
+	if err != nil {
+		return t, ErrBadSyntax
+	}
+
+	stop, err := strconv.ParseUint(value[sp+1:], 10, 64)
+	if err != nil {
+		return t, ErrBadSyntax
+	}
+
+	t.Start, t.Stop = start, stop
+
+	return t, nil
+}
+
+// Permanent reports whether the session is not bounded in time at all
+func (t TimeDescription) Permanent() bool {
+	return t.Start == 0 && t.Stop == 0
+}
+
+// ntpEpochOffset is the number of seconds between 1900-01-01 and 1970-01-01
+const ntpEpochOffset = 2208988800
+
+// NTPTime converts the NTP timestamp into time.Time. Zero is converted into zero time
+func NTPTime(ntp uint64) time.Time {
+	if ntp == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(int64(ntp)-ntpEpochOffset, 0).UTC()
+}
+
+// TimeToNTP converts time.Time into the NTP timestamp. Zero time is converted into zero
+func TimeToNTP(t time.Time) uint64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return uint64(t.Unix() + ntpEpochOffset)
+}
+
+// Repeat is the r= field, following the grammar:
+//
+//	repeat-field = %s"r" "=" repeat-interval SP typed-time 1*(SP typed-time)
+//	repeat-interval = POS-DIGIT *DIGIT [fixed-len-time-unit]
+type Repeat struct {
+	Interval TypedTime
+	Duration TypedTime
+	Offsets  []TypedTime
+}
+
+func (r Repeat) Parse(value string) (Repeat, error) {
+	fields := strings.Fields(value)
+	if len(fields) < 3 {
+		return r, ErrBadSyntax
+	}
+
+	var err error
+
+	if r.Interval, err = parseTypedTime(fields[0]); err != nil {
+		return r, err
+	}
+
+	if r.Interval.Seconds == 0 {
+		return r, ErrBadSyntax
+	}
+
+	if r.Duration, err = parseTypedTime(fields[1]); err != nil {
+		return r, err
+	}
+
+	r.Offsets = make([]TypedTime, len(fields)-2)
+	for i, field := range fields[2:] {
+		if r.Offsets[i], err = parseTypedTime(field); err != nil {
+			return r, err
+		}
+	}
+
+	return r, nil
+}
+
+// ZoneAdjustment is a single pair of the z= field, following the grammar:
+//
+//	zone-field = %s"z" "=" time SP ["-"] typed-time *(SP time SP ["-"] typed-time)
+type ZoneAdjustment struct {
+	// Time is the NTP timestamp the adjustment takes place at
+	Time   uint64
+	Offset TypedTime
+}
+
+func parseZoneAdjustments(value string) ([]ZoneAdjustment, error) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 || len(fields)%2 != 0 {
+		return nil, ErrBadSyntax
+	}
+
+	adjustments := make([]ZoneAdjustment, len(fields)/2)
+
+	for i := range adjustments {
+		at, err := strconv.ParseUint(fields[i*2], 10, 64)
+		if err != nil {
+			return nil, ErrBadSyntax
+		}
+
+		field := fields[i*2+1]
+		negative := len(field) > 0 && field[0] == '-'
+		if negative {
+			field = field[1:]
+		}
+
+		offset, err := parseTypedTime(field)
+		if err != nil {
+			return nil, err
+		}
+
+		if negative {
+			offset.Seconds = -offset.Seconds
+		}
+
+		adjustments[i] = ZoneAdjustment{
+			Time:   at,
+			Offset: offset,
+		}
+	}
+
+	return adjustments, nil
+}
+
+// TypedTime is an amount of seconds, that may be written in the compact form with a
+// unit suffix: d (days), h (hours), m (minutes) or s (seconds). The unit is kept, so
+// the value is written back the same way it was received
+type TypedTime struct {
+	Seconds int64
+	// Unit is one of 'd', 'h', 'm', 's' or zero, if the value has no suffix
+	Unit byte
+}
+
+// parseTypedTime parses the unsigned typed time. The sign, where allowed, must be
+// handled by the caller
+func parseTypedTime(value string) (t TypedTime, err error) {
+	if len(value) == 0 {
+		return t, ErrBadSyntax
+	}
+
+	multiplier := int64(1)
+	if unit := value[len(value)-1]; unitSeconds(unit) != 0 {
+		t.Unit, multiplier = unit, unitSeconds(unit)
+		value = value[:len(value)-1]
+	}
+
+	if len(value) == 0 || value[0] < '0' || value[0] > '9' {
+		// strconv accepts the sign otherwise
+		return t, ErrBadSyntax
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n > math.MaxInt64/multiplier {
+		return t, ErrBadSyntax
+	}
+
+	t.Seconds = n * multiplier
+
+	return t, nil
+}
+
+// unitSeconds returns the number of seconds in the unit, or 0 if the unit is unknown
+func unitSeconds(unit byte) int64 {
+	switch unit {
+	case 'd':
+		return 86400
+	case 'h':
+		return 3600
+	case 'm':
+		return 60
+	case 's':
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Duration returns the typed time as time.Duration
+func (t TypedTime) Duration() time.Duration {
+	return time.Duration(t.Seconds) * time.Second
+}
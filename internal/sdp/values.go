@@ -20,8 +20,8 @@ const (
 
 type Origin struct {
 	Username       string
-	SessId         string // TODO: these may be ordinary integers
-	SessVersion    string // TODO: these may be ordinary integers
+	SessId         uint64
+	SessVersion    uint64
 	NetType        NetType
 	AddrType       AddrType
 	UnicastAddress string
@@ -40,14 +40,23 @@ func (o Origin) Parse(value string) (Origin, error) {
 		return o, ErrBadSyntax
 	}
 
-	o.SessId, value = value[:sp], value[sp+1:]
+	var err error
+	if o.SessId, err = parseDigits(value[:sp]); err != nil {
+		return o, err
+	}
+
+	value = value[sp+1:]
 
 	sp = strings.IndexByte(value, ' ')
 	if sp == -1 {
 		return o, ErrBadSyntax
 	}
 
-	o.SessVersion, value = value[:sp], value[sp+1:]
+	if o.SessVersion, err = parseDigits(value[:sp]); err != nil {
+		return o, err
+	}
+
+	value = value[sp+1:]
 
 	sp = strings.IndexByte(value, ' ')
 	if sp == -1 {
@@ -91,36 +100,62 @@ func (o Origin) Parse(value string) (Origin, error) {
 type BandwidthType string
 
 const (
-	UnknownBWType BandwidthType = ""
-	CT            BandwidthType = "CT"
-	AS            BandwidthType = "AS"
+	// CT and AS are measured in kilobits per second. See RFC 8866 5.8
+	CT BandwidthType = "CT"
+	AS BandwidthType = "AS"
+	// TIAS is the transport independent application specific maximum in bits per
+	// second. See RFC 3890
+	TIAS BandwidthType = "TIAS"
+	// RS and RR are RTCP bandwidths allocated to active senders and other
+	// participants in bits per second. See RFC 3556
+	RS BandwidthType = "RS"
+	RR BandwidthType = "RR"
 )
 
+// Known reports whether the bandwidth type is one of the supported ones
+func (b BandwidthType) Known() bool {
+	switch b {
+	case CT, AS, TIAS, RS, RR:
+		return true
+	default:
+		return false
+	}
+}
+
+// Experimental reports whether the bandwidth type is an X- extension
+func (b BandwidthType) Experimental() bool {
+	return strings.HasPrefix(string(b), "X-")
+}
+
 type Bandwidth struct {
+	// Type is kept as received, even if unknown
 	Type  BandwidthType
 	Value int
+	// Raw is the value of the unknown bandwidth type as received. It's kept, so the
+	// field round-trips verbatim. Value is set too, if it's a number
+	Raw string
 }
 
 func (b Bandwidth) Parse(value string) (bandwidth Bandwidth, err error) {
 	colon := strings.IndexByte(value, ':')
-	if colon == -1 {
+	if colon <= 0 {
 		return b, ErrBadSyntax
 	}
 
-	btype, bvalue := value[:colon], value[colon+1:]
-	switch btype {
-	case "CT":
-		b.Type = CT
-	case "AS":
-		b.Type = AS
-	default:
-		b.Type = UnknownBWType
-		return b, nil
-	}
+	b.Type = BandwidthType(value[:colon])
+	bvalue := value[colon+1:]
 
 	b.Value, err = strconv.Atoi(bvalue)
-	if err != nil {
-		return b, err
+	if err != nil || b.Value < 0 {
+		if b.Type.Known() {
+			return b, ErrBadSyntax
+		}
+
+		b.Value = 0
+	}
+
+	if !b.Type.Known() {
+		b.Raw = bvalue
 	}
 
 	return b, nil
@@ -183,9 +218,11 @@ func (a Attribute) Parse(value string) Attribute {
 }
 
 type ConnectionInfo struct {
-	NetType   NetType
-	AddrType  AddrType
-	Address   string
+	NetType  NetType
+	AddrType AddrType
+	Address  string
+	// TTL is the time to live of IP4 multicast addresses. Zero or negative means it's
+	// absent. Parsed descriptions have -1 then
 	TTL       int
 	AddrRange int
 }
@@ -251,26 +288,36 @@ func parseAddress(addr string, typ AddrType) (outAddr string, ttl, addrrange int
 	var rawTTL string
 	addr, rawTTL = addr[:slash], addr[slash+1:]
 	if slash = strings.IndexByte(rawTTL, '/'); slash != -1 {
+		if typ == IP6 {
+			// IP6 has no TTL, so there may be only the addr-range
+			return "", -1, 0, ErrBadSyntax
+		}
+
 		addrrange, err = strconv.Atoi(rawTTL[slash+1:])
-		if err != nil {
-			return "", -1, 0, err
+		if err != nil || addrrange < 1 {
+			return "", -1, 0, ErrBadSyntax
 		}
 
 		rawTTL = rawTTL[:slash]
 	}
 
 	ttl, err = strconv.Atoi(rawTTL)
-	if err != nil {
-		return "", -1, 0, err
+	if err != nil || ttl < 0 {
+		return "", -1, 0, ErrBadSyntax
 	}
 
 	if typ == IP6 {
-		// Don't forget! IP6 doesn't have TTL.
-		// Also, if we're at this point, so TTL is already presented. Then
-		// just swap ttl with addrrange and set ttl to 0,
-		// (!) ignoring possible error: both ttl and addrrange are presented
+		// Don't forget! IP6 doesn't have TTL. So the only value after the slash
+		// is the addr-range
+		if ttl < 1 {
+			return "", -1, 0, ErrBadSyntax
+		}
+
 		addrrange = ttl
 		ttl = -1
+	} else if ttl > 255 {
+		// TTL of IP4 is a single octet
+		return "", -1, 0, ErrBadSyntax
 	}
 
 	return addr, ttl, addrrange, nil
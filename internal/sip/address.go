@@ -0,0 +1,199 @@
+package sip
+
+import "strings"
+
+// Address is the value of the From, To, Contact and alike headers. It follows the
+// following grammar:
+//
+//	( name-addr / addr-spec ) *( SEMI generic-param )
+//	name-addr = [ display-name ] LAQUOT addr-spec RAQUOT
+//
+// The URI is kept raw, as it's mostly needed only to be forwarded further.
+type Address struct {
+	DisplayName string
+	URI         string
+	// Params contains header parameters (like tag) without the leading semicolon
+	Params string
+}
+
+func (a Address) Parse(value string) (Address, error) {
+	value = strings.TrimSpace(value)
+
+	switch laquot := strings.IndexByte(value, '<'); {
+	case laquot != -1:
+		raquot := strings.IndexByte(value[laquot:], '>')
+		if raquot == -1 {
+			return a, ErrBadRequest
+		}
+
+		a.DisplayName = strings.TrimSpace(value[:laquot])
+		if len(a.DisplayName) > 1 && a.DisplayName[0] == '"' {
+			if a.DisplayName[len(a.DisplayName)-1] != '"' {
+				return a, ErrBadRequest
+			}
+
+			a.DisplayName = a.DisplayName[1 : len(a.DisplayName)-1]
+		}
+
+		a.URI, value = value[laquot+1:laquot+raquot], value[laquot+raquot+1:]
+	default:
+		// in addr-spec form, parameters after the URI belong to the header,
+		// not to the URI itself
+		semicolon := strings.IndexByte(value, ';')
+		if semicolon == -1 {
+			semicolon = len(value)
+		}
+
+		a.URI, value = value[:semicolon], value[semicolon:]
+	}
+
+	if len(a.URI) == 0 || strings.IndexByte(a.URI, ':') == -1 {
+		return a, ErrBadRequest
+	}
+
+	value = strings.TrimSpace(value)
+	if len(value) > 0 {
+		if value[0] != ';' {
+			return a, ErrBadRequest
+		}
+
+		a.Params = value[1:]
+	}
+
+	return a, nil
+}
+
+func (a Address) String() string {
+	var b strings.Builder
+	b.Grow(len(a.DisplayName) + len(a.URI) + len(a.Params) + 6)
+
+	if len(a.DisplayName) > 0 {
+		b.WriteByte('"')
+		b.WriteString(a.DisplayName)
+		b.WriteString(`" `)
+	}
+
+	b.WriteByte('<')
+	b.WriteString(a.URI)
+	b.WriteByte('>')
+
+	if len(a.Params) > 0 {
+		b.WriteByte(';')
+		b.WriteString(a.Params)
+	}
+
+	return b.String()
+}
+
+// Scheme returns the URI scheme in lower case, e.g. sip, sips or tel
+func (a Address) Scheme() string {
+	colon := strings.IndexByte(a.URI, ':')
+	if colon == -1 {
+		return ""
+	}
+
+	return strings.ToLower(a.URI[:colon])
+}
+
+// User returns the user part of a sip or sips URI, or the subscriber number of a tel
+// URI. URI parameters are stripped in both cases
+func (a Address) User() string {
+	colon := strings.IndexByte(a.URI, ':')
+	if colon == -1 {
+		return ""
+	}
+
+	rest := a.URI[colon+1:]
+
+	if a.Scheme() != "tel" {
+		at := strings.IndexByte(rest, '@')
+		if at == -1 {
+			return ""
+		}
+
+		rest = rest[:at]
+		if colon = strings.IndexByte(rest, ':'); colon != -1 {
+			// password is not a part of the user
+			rest = rest[:colon]
+		}
+	}
+
+	if semicolon := strings.IndexByte(rest, ';'); semicolon != -1 {
+		rest = rest[:semicolon]
+	}
+
+	return rest
+}
+
+// Param returns the value of the header parameter. Parameters without value are
+// reported as found with an empty value
+func (a Address) Param(key string) (value string, found bool) {
+	params := a.Params
+
+	for len(params) > 0 {
+		var param string
+
+		if semicolon := strings.IndexByte(params, ';'); semicolon != -1 {
+			param, params = params[:semicolon], params[semicolon+1:]
+		} else {
+			param, params = params, ""
+		}
+
+		name := param
+		if eq := strings.IndexByte(param, '='); eq != -1 {
+			name, value = param[:eq], param[eq+1:]
+		} else {
+			value = ""
+		}
+
+		if strings.EqualFold(strings.TrimSpace(name), key) {
+			return strings.TrimSpace(value), true
+		}
+	}
+
+	return "", false
+}
+
+// ParseAddressList parses the comma-separated list of addresses, like the one of the
+// Contact or P-Asserted-Identity headers. Commas inside quoted display names and angle
+// brackets don't split the list
+func ParseAddressList(value string) ([]Address, error) {
+	var (
+		addrs    []Address
+		quoted   bool
+		brackets bool
+		start    int
+	)
+
+	for i := 0; i <= len(value); i++ {
+		if i < len(value) {
+			switch value[i] {
+			case '"':
+				quoted = !quoted
+				continue
+			case '<':
+				brackets = !quoted
+				continue
+			case '>':
+				brackets = false
+				continue
+			case ',':
+				if quoted || brackets {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+
+		addr, err := Address{}.Parse(value[start:i])
+		if err != nil {
+			return nil, err
+		}
+
+		addrs = append(addrs, addr)
+		start = i + 1
+	}
+
+	return addrs, nil
+}
@@ -0,0 +1,78 @@
+package sip
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddress(t *testing.T) {
+	t.Run("name-addr", func(t *testing.T) {
+		addr, err := Address{}.Parse(`"Alice, the caller" <sip:+12155551212:secret@atlanta.com;user=phone>;tag=1928301774`)
+		require.NoError(t, err)
+		require.Equal(t, "Alice, the caller", addr.DisplayName)
+		require.Equal(t, "sip:+12155551212:secret@atlanta.com;user=phone", addr.URI)
+		require.Equal(t, "sip", addr.Scheme())
+		require.Equal(t, "+12155551212", addr.User())
+		tag, found := addr.Param("tag")
+		require.True(t, found)
+		require.Equal(t, "1928301774", tag)
+		_, found = addr.Param("user")
+		require.False(t, found, "URI parameters mustn't be treated as header ones")
+	})
+
+	t.Run("addr-spec", func(t *testing.T) {
+		addr, err := Address{}.Parse("tel:+1-408-555-1212;tag=abc")
+		require.NoError(t, err)
+		require.Empty(t, addr.DisplayName)
+		require.Equal(t, "tel:+1-408-555-1212", addr.URI)
+		require.Equal(t, "+1-408-555-1212", addr.User())
+		require.Equal(t, "<tel:+1-408-555-1212>;tag=abc", addr.String())
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		for _, sample := range []string{"", "Alice <sip:alice@atlanta.com", "<alice>", "<sip:a@b> tag"} {
+			_, err := Address{}.Parse(sample)
+			require.Errorf(t, err, "sample: %q", sample)
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		addrs, err := ParseAddressList(`"Doe, John" <sip:john@example.com>, <sip:a@b.com;x=1,2>,tel:+123`)
+		require.NoError(t, err)
+		require.Len(t, addrs, 3)
+		require.Equal(t, "Doe, John", addrs[0].DisplayName)
+		require.Equal(t, "sip:a@b.com;x=1,2", addrs[1].URI)
+		require.Equal(t, "tel:+123", addrs[2].URI)
+	})
+}
+
+func TestIdentities(t *testing.T) {
+	ids, err := Identities{}.Parse(`"Cullen Jennings" <sip:fluffy@cisco.com>, <tel:+14085550101>`)
+	require.NoError(t, err)
+	sipID, found := ids.SIP()
+	require.True(t, found)
+	require.Equal(t, "sip:fluffy@cisco.com", sipID.URI)
+	tel, found := ids.Tel()
+	require.True(t, found)
+	require.Equal(t, "tel:+14085550101", tel.URI)
+	require.Equal(t, `"Cullen Jennings" <sip:fluffy@cisco.com>, <tel:+14085550101>`, ids.String())
+
+	_, err = Identities{}.Parse("<sip:a@atlanta.com>, <sip:b@atlanta.com>")
+	require.Error(t, err, "two sip identities must be rejected")
+	_, err = Identities{}.Parse("<mailto:a@atlanta.com>")
+	require.Equal(t, ErrUnsupportedURIScheme, err)
+}
+
+func TestPrivacy(t *testing.T) {
+	privacy, err := Privacy(0).Parse("header; ID;unknown;critical")
+	require.NoError(t, err)
+	require.True(t, privacy.Has(PrivacyHeaders|PrivacyID|PrivacyCritical))
+	require.False(t, privacy.Has(PrivacyUser))
+	require.Equal(t, "header;critical;id", privacy.String())
+
+	_, err = Privacy(0).Parse("none;id")
+	require.Error(t, err)
+	_, err = Privacy(0).Parse("id;;user")
+	require.Error(t, err)
+}
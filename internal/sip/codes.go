@@ -44,6 +44,10 @@ const (
 	BadExtension                Code = 420 // RFC 3261 21.4.15
 	ExtensionRequired           Code = 421 // RFC 3261 21.4.16
 	IntervalTooBrief            Code = 423 // RFC 3261 21.4.17
+	UseIdentityHeader           Code = 428 // RFC 8224 6.2.2
+	BadIdentityInfo             Code = 436 // RFC 8224 6.2.2
+	UnsupportedCredential       Code = 437 // RFC 8224 6.2.2
+	InvalidIdentityHeader       Code = 438 // RFC 8224 6.2.2
 	TemporarilyUnavailable      Code = 480 // RFC 3261 21.4.18
 	CallTransactionDoesNotExist Code = 481 // RFC 3261 21.4.19
 	LoopDetected                Code = 482 // RFC 3261 21.4.20
@@ -134,6 +138,14 @@ func Text(code Code) Status {
 		return "Extension Required"
 	case IntervalTooBrief:
 		return "Interval Too Brief"
+	case UseIdentityHeader:
+		return "Use Identity Header"
+	case BadIdentityInfo:
+		return "Bad Identity Info"
+	case UnsupportedCredential:
+		return "Unsupported Credential"
+	case InvalidIdentityHeader:
+		return "Invalid Identity Header"
 	case TemporarilyUnavailable:
 		return "Temporarily Unavailable"
 	case CallTransactionDoesNotExist:
@@ -258,6 +270,14 @@ func CodeStatus(code Code) string {
 		return "421 Extension Required\r\n"
 	case IntervalTooBrief:
 		return "423 Interval Too Brief\r\n"
+	case UseIdentityHeader:
+		return "428 Use Identity Header\r\n"
+	case BadIdentityInfo:
+		return "436 Bad Identity Info\r\n"
+	case UnsupportedCredential:
+		return "437 Unsupported Credential\r\n"
+	case InvalidIdentityHeader:
+		return "438 Invalid Identity Header\r\n"
 	case TemporarilyUnavailable:
 		return "480 Temporarily Unavailable\r\n"
 	case CallTransactionDoesNotExist:
@@ -0,0 +1,29 @@
+package sip
+
+import "github.com/indigo-web/utils/uf"
+
+// poisonByte overwrites every string handed out by the Parser when it's released in
+// debug builds. Any code that still holds such a string will then see garbage instead of
+// silently reading the next request's data
+const poisonByte = 0xDB
+
+func poison(s string) {
+	b := uf.S2B(s)
+	for i := range b {
+		b[i] = poisonByte
+	}
+}
+
+func poisoned(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != poisonByte {
+			return false
+		}
+	}
+
+	return true
+}
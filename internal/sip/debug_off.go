@@ -0,0 +1,5 @@
+//go:build !blu_debug
+
+package sip
+
+const debugRelease = false
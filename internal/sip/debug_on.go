@@ -0,0 +1,8 @@
+//go:build blu_debug
+
+package sip
+
+// debugRelease enables tracking of the strings produced by the Parser. They are poisoned
+// on Parser.Release, so a use-after-Release becomes visible instead of silently reading
+// the reused arena memory
+const debugRelease = true
@@ -0,0 +1,32 @@
+//go:build blu_debug
+
+package sip
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUseAfterRelease(t *testing.T) {
+	data := "" +
+		"INVITE sip:bob@biloxi.com SIP/2.0\r\n" +
+		"Call-ID: a84b4c76e66710@pc33.atlanta.com\r\n\r\n"
+
+	request := NewRequest()
+	p := newParser(request)
+	done, err := p.Parse([]byte(data))
+	require.NoError(t, err)
+	require.True(t, done)
+
+	kept := *request
+	callID, _ := request.Headers.Get("Call-ID")
+	clone := request.Clone()
+	require.False(t, kept.Stale())
+
+	p.Release()
+	require.True(t, kept.Stale(), "request kept by value must be detected as stale")
+	require.True(t, poisoned(callID))
+	require.False(t, clone.Stale())
+	require.Equal(t, "INVITE", clone.Method)
+}
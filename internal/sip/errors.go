@@ -30,6 +30,10 @@ var (
 	ErrUnsupportedURIScheme        = NewError(UnsupportedURIScheme, "bad unsupported URI scheme")
 	ErrExtensionRequired           = NewError(ExtensionRequired, "extension required")
 	ErrIntervalTooBrief            = NewError(IntervalTooBrief, "interval too brief")
+	ErrUseIdentityHeader           = NewError(UseIdentityHeader, "use identity header")
+	ErrBadIdentityInfo             = NewError(BadIdentityInfo, "bad identity info")
+	ErrUnsupportedCredential       = NewError(UnsupportedCredential, "unsupported credential")
+	ErrInvalidIdentityHeader       = NewError(InvalidIdentityHeader, "invalid identity header")
 	ErrTemporarilyUnavailable      = NewError(TemporarilyUnavailable, "temporarily unavailable")
 	ErrCallTransactionDoesNotExist = NewError(CallTransactionDoesNotExist, "call/transaction does not exist")
 	ErrLoopDetected                = NewError(LoopDetected, "loop detected")
@@ -0,0 +1,110 @@
+package sip
+
+// knownHeaders maps the lower-cased and compact header names into the canonical ones.
+// See RFC 3261 7.3.3 and the IANA SIP header fields registry
+var knownHeaders = map[string]string{
+	"accept":               "Accept",
+	"accept-contact":       "Accept-Contact",
+	"accept-encoding":      "Accept-Encoding",
+	"accept-language":      "Accept-Language",
+	"alert-info":           "Alert-Info",
+	"allow":                "Allow",
+	"allow-events":         "Allow-Events",
+	"authentication-info":  "Authentication-Info",
+	"authorization":        "Authorization",
+	"call-id":              "Call-ID",
+	"call-info":            "Call-Info",
+	"contact":              "Contact",
+	"content-disposition":  "Content-Disposition",
+	"content-encoding":     "Content-Encoding",
+	"content-language":     "Content-Language",
+	"content-length":       "Content-Length",
+	"content-type":         "Content-Type",
+	"cseq":                 "CSeq",
+	"date":                 "Date",
+	"error-info":           "Error-Info",
+	"event":                "Event",
+	"expires":              "Expires",
+	"from":                 "From",
+	"identity":             "Identity",
+	"in-reply-to":          "In-Reply-To",
+	"max-forwards":         "Max-Forwards",
+	"min-expires":          "Min-Expires",
+	"mime-version":         "MIME-Version",
+	"organization":         "Organization",
+	"p-asserted-identity":  "P-Asserted-Identity",
+	"p-preferred-identity": "P-Preferred-Identity",
+	"priority":             "Priority",
+	"privacy":              "Privacy",
+	"proxy-authenticate":   "Proxy-Authenticate",
+	"proxy-authorization":  "Proxy-Authorization",
+	"proxy-require":        "Proxy-Require",
+	"record-route":         "Record-Route",
+	"refer-to":             "Refer-To",
+	"referred-by":          "Referred-By",
+	"reject-contact":       "Reject-Contact",
+	"reply-to":             "Reply-To",
+	"request-disposition":  "Request-Disposition",
+	"require":              "Require",
+	"retry-after":          "Retry-After",
+	"route":                "Route",
+	"server":               "Server",
+	"session-expires":      "Session-Expires",
+	"subject":              "Subject",
+	"supported":            "Supported",
+	"timestamp":            "Timestamp",
+	"to":                   "To",
+	"unsupported":          "Unsupported",
+	"user-agent":           "User-Agent",
+	"via":                  "Via",
+	"warning":              "Warning",
+	"www-authenticate":     "WWW-Authenticate",
+
+	"a": "Accept-Contact",
+	"b": "Referred-By",
+	"c": "Content-Type",
+	"d": "Request-Disposition",
+	"e": "Content-Encoding",
+	"f": "From",
+	"i": "Call-ID",
+	"j": "Reject-Contact",
+	"k": "Supported",
+	"l": "Content-Length",
+	"m": "Contact",
+	"o": "Event",
+	"r": "Refer-To",
+	"s": "Subject",
+	"t": "To",
+	"u": "Allow-Events",
+	"v": "Via",
+	"x": "Session-Expires",
+	"y": "Identity",
+}
+
+// maxKnownHeader is the length of the longest name in knownHeaders
+const maxKnownHeader = len("p-preferred-identity")
+
+// CanonicalHeader returns the canonical spelling of the header name. Header names are
+// case-insensitive and may come in the compact form, so they must be canonicalized
+// once they're received, for the lookups to be exact. Unknown names are returned as is
+func CanonicalHeader(name string) string {
+	if len(name) > maxKnownHeader {
+		return name
+	}
+
+	var lower [maxKnownHeader]byte
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+
+		lower[i] = c
+	}
+
+	if canonical, found := knownHeaders[string(lower[:len(name)])]; found {
+		return canonical
+	}
+
+	return name
+}
@@ -0,0 +1,156 @@
+package sip
+
+import "strings"
+
+// Header names of the asserted identity and privacy extensions. See RFC 3323 and
+// RFC 3325
+const (
+	PAssertedIdentity  = "P-Asserted-Identity"
+	PPreferredIdentity = "P-Preferred-Identity"
+	PrivacyHeader      = "Privacy"
+)
+
+// Identities is the value of the P-Asserted-Identity and P-Preferred-Identity headers.
+// It contains at most two addresses: a sip or sips URI, and a tel URI
+type Identities []Address
+
+func (i Identities) Parse(value string) (Identities, error) {
+	addrs, err := ParseAddressList(value)
+	if err != nil {
+		return i, err
+	}
+
+	if len(addrs) == 0 || len(addrs) > 2 {
+		return i, ErrBadRequest
+	}
+
+	if len(addrs) == 2 && (addrs[0].Scheme() == "tel") == (addrs[1].Scheme() == "tel") {
+		// two identities are allowed only if one of them is a tel URI
+		return i, ErrBadRequest
+	}
+
+	for _, addr := range addrs {
+		switch addr.Scheme() {
+		case "sip", "sips", "tel":
+		default:
+			return i, ErrUnsupportedURIScheme
+		}
+	}
+
+	return append(i[:0], addrs...), nil
+}
+
+func (i Identities) String() string {
+	values := make([]string, len(i))
+	for j := range i {
+		values[j] = i[j].String()
+	}
+
+	return strings.Join(values, ", ")
+}
+
+// SIP returns the sip or sips identity, if presented
+func (i Identities) SIP() (Address, bool) {
+	for _, addr := range i {
+		if addr.Scheme() != "tel" {
+			return addr, true
+		}
+	}
+
+	return Address{}, false
+}
+
+// Tel returns the tel identity, if presented
+func (i Identities) Tel() (Address, bool) {
+	for _, addr := range i {
+		if addr.Scheme() == "tel" {
+			return addr, true
+		}
+	}
+
+	return Address{}, false
+}
+
+// Privacy is the set of privacy types requested by the Privacy header
+type Privacy uint8
+
+const (
+	// PrivacyHeaders requests obscuring the headers that might identify the user
+	PrivacyHeaders Privacy = 1 << iota
+	// PrivacySession requests anonymization of the session (media) description
+	PrivacySession
+	// PrivacyUser requests user-level privacy, provided by the user agent itself
+	PrivacyUser
+	// PrivacyNone explicitly requests no privacy at all
+	PrivacyNone
+	// PrivacyCritical makes the request fail if any of the privacy types can't be provided
+	PrivacyCritical
+	// PrivacyID requests removal of the asserted identity when the request leaves the
+	// trust domain. See RFC 3325 9.3
+	PrivacyID
+)
+
+var privacyValues = [...]struct {
+	name  string
+	value Privacy
+}{
+	{"header", PrivacyHeaders},
+	{"session", PrivacySession},
+	{"user", PrivacyUser},
+	{"none", PrivacyNone},
+	{"critical", PrivacyCritical},
+	{"id", PrivacyID},
+}
+
+// Parse parses the Privacy header value, following the grammar:
+//
+//	priv-value *( ";" priv-value )
+//
+// Unknown values are ignored, as the RFC defines the list as extensible.
+func (p Privacy) Parse(value string) (Privacy, error) {
+	for len(value) > 0 {
+		var token string
+
+		if semicolon := strings.IndexByte(value, ';'); semicolon != -1 {
+			token, value = value[:semicolon], value[semicolon+1:]
+		} else {
+			token, value = value, ""
+		}
+
+		token = strings.TrimSpace(token)
+		if len(token) == 0 {
+			return p, ErrBadRequest
+		}
+
+		for _, known := range privacyValues {
+			if strings.EqualFold(token, known.name) {
+				p |= known.value
+				break
+			}
+		}
+	}
+
+	if p&PrivacyNone != 0 && p != PrivacyNone {
+		// none can't be combined with any other value
+		return p, ErrBadRequest
+	}
+
+	return p, nil
+}
+
+func (p Privacy) String() string {
+	values := make([]string, 0, len(privacyValues))
+
+	for _, known := range privacyValues {
+		if p&known.value != 0 {
+			values = append(values, known.name)
+		}
+	}
+
+	return strings.Join(values, ";")
+}
+
+// Has reports whether all the privacy types are requested
+func (p Privacy) Has(types Privacy) bool {
+	return p&types == types
+}
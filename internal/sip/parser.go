@@ -2,7 +2,6 @@ package sip
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/gokiki/sip-server/settings"
 	"github.com/indigo-web/utils/arena"
@@ -26,6 +25,9 @@ type Parser struct {
 	urlEncodedChar uint8
 	bodyBuff       []byte
 	state          parserState
+	// issued holds every arena-backed string handed out since the last Release. Used
+	// in debug builds only
+	issued []string
 }
 
 func NewParser(
@@ -127,7 +129,7 @@ method:
 		case '\r', '\n':
 			return true, ErrBadRequest
 		case ' ':
-			p.request.Method = uf.B2S(p.requestLineArena.Finish())
+			p.request.Method = p.issue(p.requestLineArena.Finish())
 			data = data[i+1:]
 			p.counter = 0
 			p.state = eUriScheme
@@ -157,12 +159,12 @@ uriUser:
 	for i := range data {
 		switch data[i] {
 		case '@':
-			p.request.URI.User = uf.B2S(p.requestLineArena.Finish())
+			p.request.URI.User = p.issue(p.requestLineArena.Finish())
 			data = data[i+1:]
 			p.state = eUriHost
 			goto uriHost
 		case ':':
-			p.request.URI.User = uf.B2S(p.requestLineArena.Finish())
+			p.request.URI.User = p.issue(p.requestLineArena.Finish())
 			data = data[i+1:]
 			p.state = eUriPassword
 			goto uriPassword
@@ -214,7 +216,7 @@ uriPassword:
 	for i := range data {
 		switch data[i] {
 		case '@':
-			p.request.URI.Password = uf.B2S(p.requestLineArena.Finish())
+			p.request.URI.Password = p.issue(p.requestLineArena.Finish())
 			data = data[i+1:]
 			p.state = eUriHost
 			goto uriHost
@@ -313,7 +315,7 @@ paramsKey:
 	for i := range data {
 		switch data[i] {
 		case '=':
-			p.tempParamKey = uf.B2S(p.requestLineArena.Finish())
+			p.tempParamKey = p.issue(p.requestLineArena.Finish())
 			data = data[i+1:]
 			p.state = eParamsValue
 			goto paramsValue
@@ -369,12 +371,12 @@ paramsValue:
 	for i := range data {
 		switch data[i] {
 		case ';':
-			p.request.URI.Params.Add(p.tempParamKey, uf.B2S(p.requestLineArena.Finish()))
+			p.request.URI.Params.Add(p.tempParamKey, p.issue(p.requestLineArena.Finish()))
 			data = data[i+1:]
 			p.state = eParamsKey
 			goto paramsKey
 		case ' ':
-			p.request.URI.Params.Add(p.tempParamKey, uf.B2S(p.requestLineArena.Finish()))
+			p.request.URI.Params.Add(p.tempParamKey, p.issue(p.requestLineArena.Finish()))
 			data = data[i+1:]
 			p.state = eProto
 			goto proto
@@ -536,7 +538,7 @@ protoCRLF:
 		return false, nil
 	}
 
-	p.request.Proto = Protocol(uf.B2S(p.requestLineArena.Finish()))
+	p.request.Proto = Protocol(p.issue(p.requestLineArena.Finish()))
 
 	switch data[0] {
 	case '\r':
@@ -581,10 +583,10 @@ headerKey:
 				return true, ErrHeaderFieldsTooLarge
 			}
 
-			p.headerKey = uf.B2S(p.headerKeyArena.Finish())
+			p.headerKey = CanonicalHeader(p.issue(p.headerKeyArena.Finish()))
 			data = data[i+1:]
 
-			if strings.EqualFold(p.headerKey, "content-length") {
+			if p.headerKey == "Content-Length" {
 				p.state = eContentLength
 				goto contentLength
 			}
@@ -741,7 +743,7 @@ headerValueCRLF:
 		return false, nil
 	}
 
-	value = uf.B2S(p.headerValueArena.Finish())
+	value = p.issue(p.headerValueArena.Finish())
 	requestHeaders.Add(p.headerKey, value)
 
 	switch data[0] {
@@ -794,10 +796,34 @@ body:
 	return true, nil
 }
 
+// Release resets the parser and reclaims its arenas. All the strings of the request
+// refer to the arenas memory, so the request mustn't be used after this call. Use
+// Request.Clone to keep it
 func (p *Parser) Release() {
 	p.request.Headers.Clear()
+	p.request.URI.Params.Clear()
+
+	if debugRelease {
+		for _, s := range p.issued {
+			poison(s)
+		}
+
+		p.issued = p.issued[:0]
+	}
+
 	p.headerKeyArena.Clear()
 	p.headerValueArena.Clear()
 	p.requestLineArena.Clear()
 	p.state = eMethod
 }
+
+// issue turns arena memory into a string. In debug builds the string is also remembered
+// in order to be poisoned on Release
+func (p *Parser) issue(b []byte) string {
+	s := uf.B2S(b)
+	if debugRelease {
+		p.issued = append(p.issued, s)
+	}
+
+	return s
+}
@@ -73,4 +73,70 @@ func TestParser(t *testing.T) {
 			assert.Equalf(t, want, value, "header's values doesn't match: %s", key)
 		}
 	})
+	t.Run("clone outlives release", func(t *testing.T) {
+		data := "" +
+			"INVITE sip:bob:secret@biloxi.com;transport=udp SIP/2.0\r\n" +
+			"Call-ID: a84b4c76e66710@pc33.atlanta.com\r\n" +
+			"CSeq: 314159 INVITE\r\n\r\n"
+
+		request := NewRequest()
+		p := newParser(request)
+		done, err := p.Parse([]byte(data))
+		require.NoError(t, err)
+		require.True(t, done)
+
+		clone := request.Clone()
+		p.Release()
+
+		done, err = p.Parse([]byte("OPTIONS sip:carol:hunter2@chicago.com SIP/2.0\r\n" +
+			"Call-ID: 00000000000000@pc33.chicago.com\r\n\r\n"))
+		require.NoError(t, err)
+		require.True(t, done)
+
+		require.Equal(t, "INVITE", clone.Method)
+		require.Equal(t, "bob", clone.URI.User)
+		require.Equal(t, "secret", clone.URI.Password)
+		require.Equal(t, "biloxi.com", clone.URI.Host)
+		require.Equal(t, "SIP/2.0", string(clone.Proto))
+		value, found := clone.URI.Params.Get("transport")
+		require.True(t, found)
+		require.Equal(t, "udp", value)
+		value, found = clone.Headers.Get("Call-ID")
+		require.True(t, found)
+		require.Equal(t, "a84b4c76e66710@pc33.atlanta.com", value)
+		value, found = clone.Headers.Get("CSeq")
+		require.True(t, found)
+		require.Equal(t, "314159 INVITE", value)
+		require.False(t, clone.Stale())
+	})
+	t.Run("canonical header names", func(t *testing.T) {
+		data := "" +
+			"INVITE sip:bob@biloxi.com;m=1;t=2 SIP/2.0\r\n" +
+			"f: Alice <sip:alice@atlanta.com>;tag=1928301774\r\n" +
+			"i: a84b4c76e66710@pc33.atlanta.com\r\n" +
+			"P-ASSERTED-IDENTITY: <sip:alice@atlanta.com>\r\n" +
+			"p-asserted-identity: <tel:+14085551212>\r\n" +
+			"X-Custom: value\r\n" +
+			"l: 4\r\n\r\n" +
+			"body"
+
+		request := NewRequest()
+		p := newParser(request)
+		done, err := p.Parse([]byte(data))
+		require.NoError(t, err)
+		require.True(t, done)
+		require.Equal(t, map[string][]string{
+			"From":                {"Alice <sip:alice@atlanta.com>;tag=1928301774"},
+			"Call-ID":             {"a84b4c76e66710@pc33.atlanta.com"},
+			"P-Asserted-Identity": {"<sip:alice@atlanta.com>", "<tel:+14085551212>"},
+			"X-Custom":            {"value"},
+		}, request.Headers.Unwrap())
+		require.Equal(t, 4, request.ContentLength)
+
+		// URI parameters aren't header names
+		_, found := request.URI.Params.Get("m")
+		require.True(t, found)
+		_, found = request.URI.Params.Get("Contact")
+		require.False(t, found)
+	})
 }
@@ -1,6 +1,10 @@
 package sip
 
-import "github.com/gokiki/sip-server/internal/header"
+import (
+	"strings"
+
+	"github.com/gokiki/sip-server/internal/header"
+)
 
 type URI struct {
 	Scheme   string
@@ -13,6 +17,18 @@ type URI struct {
 	Params header.Headers
 }
 
+// Clone returns a copy of the URI that doesn't share any memory with the original
+func (u URI) Clone() URI {
+	return URI{
+		Scheme:   strings.Clone(u.Scheme),
+		User:     strings.Clone(u.User),
+		Password: strings.Clone(u.Password),
+		Host:     strings.Clone(u.Host),
+		Port:     u.Port,
+		Params:   u.Params.Clone(),
+	}
+}
+
 type Protocol string
 
 func (p Protocol) Scheme() string {
@@ -56,3 +72,55 @@ func NewRequest() *Request {
 func (r Request) HasBody() bool {
 	return r.ContentLength > 0
 }
+
+// Clone returns a fully owned copy of the request. Strings produced by the Parser
+// refer to its arenas, which are reused after Parser.Release, so every request that
+// must outlive the Release call (stored in a transaction, passed to another goroutine,
+// etc.) must be cloned first
+func (r Request) Clone() *Request {
+	var body []byte
+	if r.Body != nil {
+		body = append(make([]byte, 0, len(r.Body)), r.Body...)
+	}
+
+	return &Request{
+		Method:        strings.Clone(r.Method),
+		URI:           r.URI.Clone(),
+		Proto:         Protocol(strings.Clone(string(r.Proto))),
+		Headers:       r.Headers.Clone(),
+		ContentLength: r.ContentLength,
+		Body:          body,
+	}
+}
+
+// Stale reports whether the request refers to memory, that was already reclaimed by
+// Parser.Release. The check is possible only in builds with the blu_debug tag, otherwise
+// false is always returned
+func (r Request) Stale() bool {
+	if !debugRelease {
+		return false
+	}
+
+	if poisoned(r.Method) || poisoned(r.URI.User) || poisoned(r.URI.Password) ||
+		poisoned(string(r.Proto)) {
+		return true
+	}
+
+	return stalePairs(r.URI.Params) || stalePairs(r.Headers)
+}
+
+func stalePairs(h header.Headers) bool {
+	for key, values := range h.Unwrap() {
+		if poisoned(key) {
+			return true
+		}
+
+		for _, value := range values {
+			if poisoned(value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
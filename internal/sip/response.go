@@ -1,3 +1,44 @@
 package sip
 
-type Response struct{}
+import (
+	"strings"
+
+	"github.com/gokiki/sip-server/internal/header"
+)
+
+type Response struct {
+	Proto         Protocol
+	Code          Code
+	Status        Status
+	Headers       header.Headers
+	ContentLength int
+	Body          []byte
+}
+
+func NewResponse() *Response {
+	return &Response{
+		Headers: header.NewHeaders(),
+	}
+}
+
+func (r Response) HasBody() bool {
+	return r.ContentLength > 0
+}
+
+// Clone returns a fully owned copy of the response, so it doesn't share any memory
+// with the buffers it was initially filled from
+func (r Response) Clone() *Response {
+	var body []byte
+	if r.Body != nil {
+		body = append(make([]byte, 0, len(r.Body)), r.Body...)
+	}
+
+	return &Response{
+		Proto:         Protocol(strings.Clone(string(r.Proto))),
+		Code:          r.Code,
+		Status:        Status(strings.Clone(string(r.Status))),
+		Headers:       r.Headers.Clone(),
+		ContentLength: r.ContentLength,
+		Body:          body,
+	}
+}
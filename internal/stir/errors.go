@@ -0,0 +1,12 @@
+package stir
+
+import "errors"
+
+var (
+	ErrBadSyntax            = errors.New("identity header is malformed")
+	ErrUnsupportedAlgorithm = errors.New("received unsupported PASSporT algorithm")
+	ErrUnsupportedType      = errors.New("received unsupported PASSporT extension")
+	ErrNotTelephoneNumber   = errors.New("URI doesn't contain a telephone number")
+	ErrBadAttestation       = errors.New("attestation must be one of A, B or C")
+	ErrBadCertURL           = errors.New("certificate URL doesn't refer to a file")
+)
@@ -0,0 +1,119 @@
+package stir
+
+import (
+	"strings"
+
+	"github.com/gokiki/sip-server/internal/sip"
+)
+
+// IdentityHeader is the name of the header defined in RFC 8224
+const IdentityHeader = "Identity"
+
+// Identity is the value of the Identity header. It follows the following grammar:
+//
+//	signed-identity-digest SEMI ident-info *( SEMI ident-info-params )
+//	ident-info = "info" EQUAL ident-info-uri
+//
+// Unknown parameters are ignored.
+type Identity struct {
+	Token string // compulsory
+	Info  string // compulsory
+	Alg   string // optional
+	Ppt   string // optional
+}
+
+func (i Identity) Parse(value string) (Identity, error) {
+	semicolon := strings.IndexByte(value, ';')
+	if semicolon == -1 {
+		return i, ErrBadSyntax
+	}
+
+	i.Token, value = strings.TrimSpace(value[:semicolon]), value[semicolon+1:]
+
+	for len(value) > 0 {
+		var param string
+
+		if semicolon = strings.IndexByte(value, ';'); semicolon != -1 {
+			param, value = value[:semicolon], value[semicolon+1:]
+		} else {
+			param, value = value, ""
+		}
+
+		eq := strings.IndexByte(param, '=')
+		if eq == -1 {
+			return i, ErrBadSyntax
+		}
+
+		key, paramValue := strings.TrimSpace(param[:eq]), strings.TrimSpace(param[eq+1:])
+
+		switch strings.ToLower(key) {
+		case "info":
+			if len(paramValue) < 2 || paramValue[0] != '<' || paramValue[len(paramValue)-1] != '>' {
+				return i, ErrBadSyntax
+			}
+
+			i.Info = paramValue[1 : len(paramValue)-1]
+		case "alg":
+			i.Alg = paramValue
+		case "ppt":
+			i.Ppt = strings.Trim(paramValue, `"`)
+		}
+	}
+
+	if len(i.Token) == 0 || len(i.Info) == 0 {
+		return i, ErrBadSyntax
+	}
+
+	return i, nil
+}
+
+func (i Identity) String() string {
+	var b strings.Builder
+	b.Grow(len(i.Token) + len(i.Info) + 32)
+	b.WriteString(i.Token)
+	b.WriteString(";info=<")
+	b.WriteString(i.Info)
+	b.WriteByte('>')
+
+	if len(i.Alg) > 0 {
+		b.WriteString(";alg=")
+		b.WriteString(i.Alg)
+	}
+
+	if len(i.Ppt) > 0 {
+		b.WriteString(";ppt=")
+		b.WriteString(i.Ppt)
+	}
+
+	return b.String()
+}
+
+// telephoneNumber extracts the telephone number from the From or To header value and
+// canonicalizes it as RFC 8224 8.3 requires: visual separators and the leading plus
+// sign are removed, so only digits are left
+func telephoneNumber(value string) (string, error) {
+	addr, err := sip.Address{}.Parse(value)
+	if err != nil {
+		return "", err
+	}
+
+	user := addr.User()
+	tn := make([]byte, 0, len(user))
+
+	for i := 0; i < len(user); i++ {
+		switch char := user[i]; {
+		case '0' <= char && char <= '9':
+			tn = append(tn, char)
+		case char == '+' && i == 0:
+		case char == '-', char == '.', char == '(', char == ')':
+		default:
+			return "", ErrNotTelephoneNumber
+		}
+	}
+
+	if len(tn) == 0 {
+		return "", ErrNotTelephoneNumber
+	}
+
+	return string(tn), nil
+}
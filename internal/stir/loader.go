@@ -0,0 +1,114 @@
+package stir
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// CertLoader fetches the signer's certificate referred by the x5u/info URL. Chains are
+// returned leaf first. Implementations are expected to cache the certificates, as the
+// same ones are referred by most of the calls
+type CertLoader interface {
+	Load(x5u string) ([]*x509.Certificate, error)
+}
+
+// FileLoader resolves the x5u URL into a file in the directory, using the last segment
+// of the URL path as a filename. URLs without a plain filename there are rejected. Both
+// PEM and DER encodings are supported. Parsed certificates are cached until the
+// modification time of the file changes, so renewed certificates are picked up without
+// a restart
+type FileLoader struct {
+	dir   string
+	mu    sync.Mutex
+	cache map[string]cachedChain
+}
+
+type cachedChain struct {
+	modTime time.Time
+	chain   []*x509.Certificate
+}
+
+func NewFileLoader(dir string) *FileLoader {
+	return &FileLoader{
+		dir:   dir,
+		cache: make(map[string]cachedChain),
+	}
+}
+
+func (f *FileLoader) Load(x5u string) ([]*x509.Certificate, error) {
+	u, err := url.Parse(x5u)
+	if err != nil {
+		return nil, err
+	}
+
+	base := path.Base(u.Path)
+	if base == "." || base == ".." || base == "/" {
+		return nil, ErrBadCertURL
+	}
+
+	filename := filepath.Join(f.dir, base)
+	info, err := os.Stat(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	cached, found := f.cache[filename]
+	f.mu.Unlock()
+
+	if found && cached.modTime.Equal(info.ModTime()) {
+		return cached.chain, nil
+	}
+
+	raw, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := parseCertificates(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	f.cache[filename] = cachedChain{modTime: info.ModTime(), chain: chain}
+	f.mu.Unlock()
+
+	return chain, nil
+}
+
+func parseCertificates(raw []byte) ([]*x509.Certificate, error) {
+	var chain []*x509.Certificate
+
+	for {
+		block, rest := pem.Decode(raw)
+		if block == nil {
+			break
+		}
+
+		raw = rest
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		chain = append(chain, cert)
+	}
+
+	if len(chain) > 0 {
+		return chain, nil
+	}
+
+	return x509.ParseCertificates(raw)
+}
@@ -0,0 +1,175 @@
+package stir
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"strings"
+)
+
+// Attestation is the level of confidence the originating service provider has in the
+// caller's right to use the calling number. See RFC 8588 4
+type Attestation string
+
+const (
+	// FullAttestation means the customer is authenticated and authorized to use the number
+	FullAttestation Attestation = "A"
+	// PartialAttestation means the customer is authenticated, but not authorized to use
+	// the number
+	PartialAttestation Attestation = "B"
+	// GatewayAttestation means the call has just entered the network, and the origin is
+	// unknown
+	GatewayAttestation Attestation = "C"
+)
+
+func (a Attestation) valid() bool {
+	switch a {
+	case FullAttestation, PartialAttestation, GatewayAttestation:
+		return true
+	default:
+		return false
+	}
+}
+
+const (
+	algES256     = "ES256"
+	typePassport = "passport"
+	pptShaken    = "shaken"
+)
+
+var b64 = base64.RawURLEncoding
+
+// Header is the JOSE header of the PASSporT. Fields are declared in lexicographic
+// order, so the JSON encoding is canonical, as RFC 8225 9 requires
+type Header struct {
+	Alg string `json:"alg"`
+	Ppt string `json:"ppt"`
+	Typ string `json:"typ"`
+	X5U string `json:"x5u"`
+}
+
+type (
+	Orig struct {
+		TN string `json:"tn"`
+	}
+
+	Dest struct {
+		TN []string `json:"tn"`
+	}
+)
+
+// Claims is the PASSporT payload with the SHAKEN extension. Fields are declared in
+// lexicographic order for the very same reason as the Header ones are
+type Claims struct {
+	Attest Attestation `json:"attest"`
+	Dest   Dest        `json:"dest"`
+	IAT    int64       `json:"iat"`
+	Orig   Orig        `json:"orig"`
+	OrigID string      `json:"origid"`
+}
+
+// PASSporT is a decoded token. SigningInput and Signature are kept in order to verify
+// the token later, once the certificate is fetched
+type PASSporT struct {
+	Header       Header
+	Claims       Claims
+	SigningInput string
+	Signature    []byte
+}
+
+// Encode returns the PASSporT in the JWS compact serialization, signed with the key
+func Encode(key *ecdsa.PrivateKey, header Header, claims Claims) (string, error) {
+	rawHeader, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+
+	rawClaims, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := b64.EncodeToString(rawHeader) + "." + b64.EncodeToString(rawClaims)
+	digest := sha256.Sum256([]byte(signingInput))
+
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	// JWS uses the fixed-length concatenation of r and s instead of ASN.1. See RFC 7518 3.4
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+
+	return signingInput + "." + b64.EncodeToString(signature), nil
+}
+
+// Decode parses the token without verifying the signature
+func Decode(token string) (p PASSporT, err error) {
+	dot := strings.LastIndexByte(token, '.')
+	if dot == -1 {
+		return p, ErrBadSyntax
+	}
+
+	p.SigningInput = token[:dot]
+	if p.Signature, err = b64.DecodeString(token[dot+1:]); err != nil {
+		return p, ErrBadSyntax
+	}
+
+	dot = strings.IndexByte(p.SigningInput, '.')
+	if dot <= 0 || dot == len(p.SigningInput)-1 {
+		// the compact form with an omitted payload isn't supported, as SHAKEN
+		// always transmits the full form
+		return p, ErrBadSyntax
+	}
+
+	if err = decodeSegment(p.SigningInput[:dot], &p.Header); err != nil {
+		return p, err
+	}
+
+	if err = decodeSegment(p.SigningInput[dot+1:], &p.Claims); err != nil {
+		return p, err
+	}
+
+	switch {
+	case p.Header.Alg != algES256:
+		return p, ErrUnsupportedAlgorithm
+	case p.Header.Ppt != pptShaken, p.Header.Typ != typePassport:
+		return p, ErrUnsupportedType
+	case !p.Claims.Attest.valid():
+		return p, ErrBadAttestation
+	}
+
+	return p, nil
+}
+
+func decodeSegment(segment string, v any) error {
+	raw, err := b64.DecodeString(segment)
+	if err != nil {
+		return ErrBadSyntax
+	}
+
+	if err = json.Unmarshal(raw, v); err != nil {
+		return ErrBadSyntax
+	}
+
+	return nil
+}
+
+// Verify checks the ES256 signature of the token against the public key
+func (p PASSporT) Verify(key *ecdsa.PublicKey) bool {
+	if len(p.Signature) != 64 || key.Curve != elliptic.P256() {
+		return false
+	}
+
+	digest := sha256.Sum256([]byte(p.SigningInput))
+	r := new(big.Int).SetBytes(p.Signature[:32])
+	s := new(big.Int).SetBytes(p.Signature[32:])
+
+	return ecdsa.Verify(key, digest[:], r, s)
+}
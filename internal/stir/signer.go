@@ -0,0 +1,82 @@
+package stir
+
+import (
+	"crypto/ecdsa"
+	"time"
+
+	"github.com/gokiki/sip-server/internal/sip"
+)
+
+// Signer is the authentication service of RFC 8224. It attests the calling number
+// of outgoing requests by adding the Identity header
+type Signer struct {
+	key *ecdsa.PrivateKey
+	x5u string
+	now func() time.Time
+}
+
+// NewSigner returns a signer using the key, whose certificate is published at x5u
+func NewSigner(key *ecdsa.PrivateKey, x5u string) *Signer {
+	return &Signer{
+		key: key,
+		x5u: x5u,
+		now: time.Now,
+	}
+}
+
+// Sign creates the PASSporT from the From and To numbers of the request and adds it
+// as the Identity header. The origID is an opaque identifier of the point the call
+// entered the network from, usually a UUID
+func (s *Signer) Sign(request *sip.Request, attest Attestation, origID string) error {
+	if !attest.valid() {
+		return ErrBadAttestation
+	}
+
+	from, found := request.Headers.Get("From")
+	if !found {
+		return sip.ErrBadRequest
+	}
+
+	orig, err := telephoneNumber(from)
+	if err != nil {
+		return err
+	}
+
+	to, found := request.Headers.Get("To")
+	if !found {
+		return sip.ErrBadRequest
+	}
+
+	dest, err := telephoneNumber(to)
+	if err != nil {
+		return err
+	}
+
+	header := Header{
+		Alg: algES256,
+		Ppt: pptShaken,
+		Typ: typePassport,
+		X5U: s.x5u,
+	}
+	claims := Claims{
+		Attest: attest,
+		Dest:   Dest{TN: []string{dest}},
+		IAT:    s.now().Unix(),
+		Orig:   Orig{TN: orig},
+		OrigID: origID,
+	}
+
+	token, err := Encode(s.key, header, claims)
+	if err != nil {
+		return err
+	}
+
+	request.Headers.Add(IdentityHeader, Identity{
+		Token: token,
+		Info:  s.x5u,
+		Alg:   algES256,
+		Ppt:   pptShaken,
+	}.String())
+
+	return nil
+}
@@ -0,0 +1,213 @@
+package stir
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gokiki/sip-server/internal/sip"
+	"github.com/stretchr/testify/require"
+)
+
+const x5u = "https://cert.example.org/passport.pem"
+
+func newCertificate(t *testing.T, dir string) (*ecdsa.PrivateKey, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "SHAKEN 1234"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	pemCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "passport.pem"), pemCert, 0o600))
+
+	return key, cert
+}
+
+func newRequest(from, to string) *sip.Request {
+	request := sip.NewRequest()
+	request.Method = string(sip.INVITE)
+	request.Headers.Add("From", from)
+	request.Headers.Add("To", to)
+
+	return request
+}
+
+func TestSignAndVerify(t *testing.T) {
+	dir := t.TempDir()
+	key, cert := newCertificate(t, dir)
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	signer := NewSigner(key, x5u)
+	verifier := NewVerifier(NewFileLoader(dir), roots)
+
+	const (
+		from = `"Alice" <sip:+1-215-555-1212@atlanta.com;user=phone>;tag=1928301774`
+		to   = `<tel:+12155551213>`
+	)
+
+	t.Run("valid", func(t *testing.T) {
+		request := newRequest(from, to)
+		require.NoError(t, signer.Sign(request, FullAttestation, "123e4567-e89b-12d3-a456-426655440000"))
+
+		value, found := request.Headers.Get(IdentityHeader)
+		require.True(t, found)
+		identity, err := Identity{}.Parse(value)
+		require.NoError(t, err)
+		require.Equal(t, x5u, identity.Info)
+		require.Equal(t, "ES256", identity.Alg)
+		require.Equal(t, "shaken", identity.Ppt)
+
+		result := verifier.Verify(request)
+		require.Equal(t, StatusValid, result.Status)
+		require.NoError(t, result.Status.Err())
+		require.Equal(t, FullAttestation, result.Claims.Attest)
+		require.Equal(t, "12155551212", result.Claims.Orig.TN)
+		require.Equal(t, []string{"12155551213"}, result.Claims.Dest.TN)
+		require.Equal(t, "123e4567-e89b-12d3-a456-426655440000", result.Claims.OrigID)
+		require.Equal(t, "TN-Validation-Passed", result.Status.Verstat())
+	})
+
+	t.Run("non-canonical header names", func(t *testing.T) {
+		request := sip.NewRequest()
+		request.Method = string(sip.INVITE)
+		// names are canonicalized by the parser the same way
+		request.Headers.Add(sip.CanonicalHeader("f"), from)
+		request.Headers.Add(sip.CanonicalHeader("TO"), to)
+		require.NoError(t, signer.Sign(request, FullAttestation, "origid"))
+
+		identity, _ := request.Headers.Get(IdentityHeader)
+		request.Headers.Delete(IdentityHeader)
+		request.Headers.Add(sip.CanonicalHeader("y"), identity)
+
+		result := verifier.Verify(request)
+		require.Equal(t, StatusValid, result.Status)
+		require.Equal(t, "12155551212", result.Claims.Orig.TN)
+	})
+
+	t.Run("no identity", func(t *testing.T) {
+		result := verifier.Verify(newRequest(from, to))
+		require.Equal(t, StatusNoIdentity, result.Status)
+		require.Equal(t, sip.ErrUseIdentityHeader, result.Status.Err())
+	})
+
+	t.Run("number mismatch", func(t *testing.T) {
+		request := newRequest(from, to)
+		require.NoError(t, signer.Sign(request, PartialAttestation, "origid"))
+		request.Headers.Set("From", "<sip:+12155559999@atlanta.com>")
+		require.Equal(t, StatusNumberMismatch, verifier.Verify(request).Status)
+	})
+
+	t.Run("stale", func(t *testing.T) {
+		request := newRequest(from, to)
+		staleSigner := NewSigner(key, x5u)
+		staleSigner.now = func() time.Time {
+			return time.Now().Add(-2 * Freshness)
+		}
+		require.NoError(t, staleSigner.Sign(request, GatewayAttestation, "origid"))
+		require.Equal(t, StatusStale, verifier.Verify(request).Status)
+	})
+
+	t.Run("bad signature", func(t *testing.T) {
+		otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		request := newRequest(from, to)
+		require.NoError(t, NewSigner(otherKey, x5u).Sign(request, FullAttestation, "origid"))
+		require.Equal(t, StatusBadSignature, verifier.Verify(request).Status)
+	})
+
+	t.Run("untrusted certificate", func(t *testing.T) {
+		request := newRequest(from, to)
+		require.NoError(t, signer.Sign(request, FullAttestation, "origid"))
+		untrusting := NewVerifier(NewFileLoader(dir), x509.NewCertPool())
+		result := untrusting.Verify(request)
+		require.Equal(t, StatusBadCertificate, result.Status)
+		require.Equal(t, sip.ErrBadIdentityInfo, result.Status.Err())
+
+		noRoots := NewVerifier(NewFileLoader(dir), nil)
+		require.Equal(t, StatusBadCertificate, noRoots.Verify(request).Status)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		request := newRequest(from, to)
+		request.Headers.Add(IdentityHeader, "garbage;info=<"+x5u+">")
+		require.Equal(t, StatusBadIdentity, verifier.Verify(request).Status)
+	})
+
+	t.Run("not a telephone number", func(t *testing.T) {
+		request := newRequest("<sip:alice@atlanta.com>", to)
+		require.ErrorIs(t, signer.Sign(request, FullAttestation, "origid"), ErrNotTelephoneNumber)
+	})
+}
+
+func TestIdentity(t *testing.T) {
+	identity, err := Identity{}.Parse(`eyJhbGciOiJFUzI1NiJ9.eyJ9.c2ln; info=<https://cert.example.org/passport.cer>;` +
+		`alg=ES256;ppt="shaken"`)
+	require.NoError(t, err)
+	require.Equal(t, Identity{
+		Token: "eyJhbGciOiJFUzI1NiJ9.eyJ9.c2ln",
+		Info:  "https://cert.example.org/passport.cer",
+		Alg:   "ES256",
+		Ppt:   "shaken",
+	}, identity)
+	require.Equal(t, "eyJhbGciOiJFUzI1NiJ9.eyJ9.c2ln;info=<https://cert.example.org/passport.cer>;"+
+		"alg=ES256;ppt=shaken", identity.String())
+
+	_, err = Identity{}.Parse("eyJhbGciOiJFUzI1NiJ9.eyJ9.c2ln;info=https://cert.example.org/passport.cer")
+	require.ErrorIs(t, err, ErrBadSyntax)
+}
+
+func TestFileLoader(t *testing.T) {
+	dir := t.TempDir()
+	_, cert := newCertificate(t, dir)
+	loader := NewFileLoader(dir)
+
+	chain, err := loader.Load(x5u)
+	require.NoError(t, err)
+	require.Equal(t, cert.Raw, chain[0].Raw)
+
+	cached, err := loader.Load(x5u)
+	require.NoError(t, err)
+	require.Same(t, chain[0], cached[0])
+
+	// the renewed certificate is picked up
+	_, renewed := newCertificate(t, dir)
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "passport.pem"), later, later))
+
+	chain, err = loader.Load(x5u)
+	require.NoError(t, err)
+	require.Equal(t, renewed.Raw, chain[0].Raw)
+
+	_, err = loader.Load("https://cert.example.org/missing.pem")
+	require.Error(t, err)
+
+	for _, bad := range []string{
+		"https://cert.example.org",
+		"https://cert.example.org/",
+		"https://cert.example.org/..",
+		"https://cert.example.org/certs/.",
+	} {
+		_, err = loader.Load(bad)
+		require.ErrorIs(t, err, ErrBadCertURL, bad)
+	}
+}
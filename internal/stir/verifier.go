@@ -0,0 +1,262 @@
+package stir
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509"
+	"time"
+
+	"github.com/gokiki/sip-server/internal/sip"
+)
+
+// Freshness is the maximal difference between the iat claim and the time of
+// verification. See RFC 8224 6.2.1
+const Freshness = 60 * time.Second
+
+type Status int
+
+const (
+	// StatusNoIdentity means the request carries no Identity header at all
+	StatusNoIdentity Status = iota
+	// StatusValid means the signature is valid and both numbers match the request
+	StatusValid
+	// StatusBadIdentity means the Identity header or the PASSporT is malformed
+	StatusBadIdentity
+	// StatusUnsupported means the PASSporT uses an unsupported algorithm or extension
+	StatusUnsupported
+	// StatusBadCertificate means the certificate couldn't be fetched or isn't trusted
+	StatusBadCertificate
+	// StatusBadSignature means the signature doesn't match the certificate
+	StatusBadSignature
+	// StatusStale means the iat claim is too far from the current time
+	StatusStale
+	// StatusNumberMismatch means the signed numbers differ from the From or To ones
+	StatusNumberMismatch
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusNoIdentity:
+		return "no identity"
+	case StatusValid:
+		return "valid"
+	case StatusBadIdentity:
+		return "bad identity"
+	case StatusUnsupported:
+		return "unsupported"
+	case StatusBadCertificate:
+		return "bad certificate"
+	case StatusBadSignature:
+		return "bad signature"
+	case StatusStale:
+		return "stale"
+	case StatusNumberMismatch:
+		return "number mismatch"
+	default:
+		return "unknown"
+	}
+}
+
+// Err returns the error the request must be rejected with, if the policy requires
+// so. For StatusValid nil is returned
+func (s Status) Err() error {
+	switch s {
+	case StatusValid:
+		return nil
+	case StatusNoIdentity:
+		return sip.ErrUseIdentityHeader
+	case StatusBadCertificate:
+		return sip.ErrBadIdentityInfo
+	case StatusUnsupported:
+		return sip.ErrUnsupportedCredential
+	case StatusStale:
+		return sip.ErrForbidden
+	default:
+		return sip.ErrInvalidIdentityHeader
+	}
+}
+
+// Verstat returns the value of the verstat tel URI parameter, that's used to pass
+// the verification result to the called party. See ATIS-1000074
+func (s Status) Verstat() string {
+	switch s {
+	case StatusValid:
+		return "TN-Validation-Passed"
+	case StatusNoIdentity:
+		return "No-TN-Validation"
+	default:
+		return "TN-Validation-Failed"
+	}
+}
+
+// Result is the outcome of the verification. Claims are filled whenever the PASSporT
+// was successfully decoded, even if it didn't pass the verification later
+type Result struct {
+	Status Status
+	Claims Claims
+}
+
+// Verifier is the verification service of RFC 8224
+type Verifier struct {
+	loader CertLoader
+	// roots are the trusted STI-CA certificates
+	roots *x509.CertPool
+	now   func() time.Time
+}
+
+// NewVerifier returns a verifier trusting only the certificates chained to the roots.
+// Nil roots trust nothing, so every Identity header fails the verification
+func NewVerifier(loader CertLoader, roots *x509.CertPool) *Verifier {
+	if roots == nil {
+		// x509 falls back to the system roots otherwise, which aren't STI-CAs
+		roots = x509.NewCertPool()
+	}
+
+	return &Verifier{
+		loader: loader,
+		roots:  roots,
+		now:    time.Now,
+	}
+}
+
+// Verify checks every Identity header of the request and returns the result of the
+// first valid one. If none is valid, the result of the first one is returned
+func (v *Verifier) Verify(request *sip.Request) Result {
+	values, found := request.Headers.GetAll(IdentityHeader)
+	if !found || len(values) == 0 {
+		return Result{Status: StatusNoIdentity}
+	}
+
+	var first Result
+
+	for i, value := range values {
+		result := v.verify(request, value)
+		if result.Status == StatusValid {
+			return result
+		}
+
+		if i == 0 {
+			first = result
+		}
+	}
+
+	return first
+}
+
+func (v *Verifier) verify(request *sip.Request, value string) (result Result) {
+	identity, err := Identity{}.Parse(value)
+	if err != nil {
+		result.Status = StatusBadIdentity
+		return result
+	}
+
+	if len(identity.Alg) > 0 && identity.Alg != algES256 {
+		result.Status = StatusUnsupported
+		return result
+	}
+
+	passport, err := Decode(identity.Token)
+	switch err {
+	case nil:
+	case ErrUnsupportedAlgorithm, ErrUnsupportedType:
+		result.Status = StatusUnsupported
+		return result
+	default:
+		result.Status = StatusBadIdentity
+		return result
+	}
+
+	result.Claims = passport.Claims
+
+	if passport.Header.X5U != identity.Info {
+		result.Status = StatusBadIdentity
+		return result
+	}
+
+	key, ok := v.publicKey(identity.Info)
+	if !ok {
+		result.Status = StatusBadCertificate
+		return result
+	}
+
+	if !passport.Verify(key) {
+		result.Status = StatusBadSignature
+		return result
+	}
+
+	if age := v.now().Sub(time.Unix(passport.Claims.IAT, 0)); age > Freshness || age < -Freshness {
+		result.Status = StatusStale
+		return result
+	}
+
+	if !numbersMatch(request, passport.Claims) {
+		result.Status = StatusNumberMismatch
+		return result
+	}
+
+	result.Status = StatusValid
+
+	return result
+}
+
+func (v *Verifier) publicKey(x5u string) (*ecdsa.PublicKey, bool) {
+	chain, err := v.loader.Load(x5u)
+	if err != nil || len(chain) == 0 {
+		return nil, false
+	}
+
+	leaf := chain[0]
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err = leaf.Verify(x509.VerifyOptions{
+		Roots:         v.roots,
+		Intermediates: intermediates,
+		CurrentTime:   v.now(),
+		// STI certificates carry no extended key usage for TLS
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return nil, false
+	}
+
+	key, ok := leaf.PublicKey.(*ecdsa.PublicKey)
+	if !ok || key.Curve != elliptic.P256() {
+		return nil, false
+	}
+
+	return key, true
+}
+
+func numbersMatch(request *sip.Request, claims Claims) bool {
+	from, found := request.Headers.Get("From")
+	if !found {
+		return false
+	}
+
+	orig, err := telephoneNumber(from)
+	if err != nil || orig != claims.Orig.TN {
+		return false
+	}
+
+	to, found := request.Headers.Get("To")
+	if !found {
+		return false
+	}
+
+	dest, err := telephoneNumber(to)
+	if err != nil {
+		return false
+	}
+
+	for _, tn := range claims.Dest.TN {
+		if tn == dest {
+			return true
+		}
+	}
+
+	return false
+}
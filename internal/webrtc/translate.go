@@ -0,0 +1,234 @@
+package webrtc
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gokiki/sip-server/internal/sdp"
+)
+
+// webrtcSessionAttrs are session-level attributes meaningful for WebRTC endpoints only
+var webrtcSessionAttrs = []string{
+	"group", "ice-ufrag", "ice-pwd", "ice-options", "ice-lite", "fingerprint", "setup",
+	"tls-id", "msid-semantic", "extmap-allow-mixed", "extmap", "identity",
+}
+
+// webrtcMediaAttrs are media-level attributes meaningful for WebRTC endpoints only
+var webrtcMediaAttrs = []string{
+	"candidate", "end-of-candidates", "ice-ufrag", "ice-pwd", "ice-options", "fingerprint",
+	"setup", "tls-id", "mid", "rtcp-fb", "rtcp-mux", "rtcp-rsize", "rtcp", "extmap",
+	"extmap-allow-mixed", "msid", "ssrc", "ssrc-group", "rid", "simulcast", "bundle-only",
+	"sctp-port", "max-message-size", "sctpmap",
+}
+
+// legacyMediaAttrs are media-level attributes of legacy endpoints, which are replaced
+// by their WebRTC counterparts
+var legacyMediaAttrs = []string{"crypto", "rtcp"}
+
+// webrtcOnlyCodecs depend on RTP/AVPF feedback, so legacy endpoints can't use them
+var webrtcOnlyCodecs = []string{"rtx", "ulpfec", "flexfec-03"}
+
+// Legacy is the profile of the plain SIP side of the bridge
+type Legacy struct {
+	// Origin is the o= line of the bridge towards the SIP side. The bridge is the
+	// originator of the translated session, so the peer's one isn't reused. The version
+	// must be bumped by the caller on each change
+	Origin   sdp.Origin
+	Address  string
+	AddrType sdp.AddrType
+	// Port returns the local port for the i-th media. Each media gets its own, as
+	// legacy endpoints don't support BUNDLE
+	Port func(i int) int
+	// SRTP enables SDES-SRTP. Otherwise, media is sent unencrypted
+	SRTP bool
+}
+
+// ToLegacy translates the WebRTC description into the one plain SIP endpoints
+// understand. The transport becomes RTP/AVP (or RTP/SAVP with SDES), bundled media
+// are split into separate m-lines with distinct ports, and ICE, DTLS, BUNDLE and
+// feedback attributes are stripped. Data channels and media offering only codecs that
+// depend on feedback are rejected
+func ToLegacy(desc sdp.Description, profile Legacy) (sdp.Description, error) {
+	// stripping allocates the media slice anew, so the media may be modified in place
+	desc.StripAttributes(webrtcSessionAttrs...)
+	desc.Session.Originator = profile.Origin
+	desc.Session.ConnectionInfo = []sdp.ConnectionInfo{connection(profile.Address, profile.AddrType)}
+	desc.Session.EncryptionKey = sdp.EncryptionKey{}
+
+	for i, media := range desc.Media {
+		rejected := !media.Proto.RTP() || (media.Port == 0 && !media.BundleOnly())
+
+		if !rejected {
+			err := removeCodecs(&desc, i, webrtcOnlyCodecs)
+			switch {
+			case err == sdp.ErrNoFormatsLeft:
+				// nothing a legacy endpoint could use is left
+				rejected = true
+			case err != nil:
+				return desc, err
+			}
+		}
+
+		desc.StripMediaAttributes(i, webrtcMediaAttrs...)
+		media := &desc.Media[i]
+		media.ConnectionInfo, media.EncryptionKey = nil, sdp.EncryptionKey{}
+
+		if rejected {
+			// such media can't be bridged, and rejected media stay rejected
+			media.Port = 0
+			continue
+		}
+
+		media.Port = profile.Port(i)
+		media.Proto = sdp.RTPAVP
+
+		if profile.SRTP {
+			media.Proto = sdp.RTPSAVP
+
+			crypto, err := sdp.NewCrypto(1, sdp.AESCM128HMACSHA180)
+			if err != nil {
+				return desc, err
+			}
+
+			media.Attributes = append(media.Attributes,
+				sdp.Attribute{Key: "crypto", Value: string(crypto.AppendTo(nil))})
+		}
+	}
+
+	return desc, nil
+}
+
+// WebRTC is the profile of the browser side of the bridge
+type WebRTC struct {
+	// Origin is the o= line of the bridge towards the browser, the same way as
+	// Legacy.Origin is
+	Origin   sdp.Origin
+	Address  string
+	AddrType sdp.AddrType
+	// Port is the single local port all the media are bundled on
+	Port        int
+	ICE         sdp.ICECredentials
+	Candidates  []sdp.Candidate
+	Fingerprint sdp.Fingerprint
+	// Setup is the DTLS role. Offers must use actpass
+	Setup sdp.Setup
+	// Feedback is added to each video payload type. Payload types of the entries are
+	// ignored
+	Feedback []sdp.RTCPFeedback
+}
+
+// ToWebRTC translates the plain SIP description into the one browsers accept. Media
+// are bundled on a single port with rtcp-mux, and ICE, DTLS and feedback attributes
+// are inserted. The transport becomes UDP/TLS/RTP/SAVPF
+func ToWebRTC(desc sdp.Description, profile WebRTC) (sdp.Description, error) {
+	// stripping allocates the media slice anew, so the media may be modified in place
+	desc.StripAttributes(webrtcSessionAttrs...)
+	desc.Session.Originator = profile.Origin
+	desc.Session.ConnectionInfo = []sdp.ConnectionInfo{connection(profile.Address, profile.AddrType)}
+	desc.Session.EncryptionKey = sdp.EncryptionKey{}
+
+	var bundle []string
+
+	for i, media := range desc.Media {
+		mid := strconv.Itoa(i)
+		desc.StripMediaAttributes(i, append(legacyMediaAttrs, webrtcMediaAttrs...)...)
+
+		m := &desc.Media[i]
+		m.ConnectionInfo, m.EncryptionKey = nil, sdp.EncryptionKey{}
+		if media.Proto.RTP() {
+			m.Proto = sdp.UDPTLSRTPSAVPF
+		}
+
+		if media.Port == 0 || !media.Proto.RTP() {
+			// the stream stays rejected, but the mid is compulsory anyway
+			m.Port = 0
+			m.Attributes = append(m.Attributes, sdp.Attribute{Key: "mid", Value: mid})
+			continue
+		}
+
+		m.Port = profile.Port
+
+		bundle = append(bundle, mid)
+		attrs := []sdp.Attribute{
+			{Key: "mid", Value: mid},
+			{Key: "ice-ufrag", Value: profile.ICE.Ufrag},
+			{Key: "ice-pwd", Value: profile.ICE.Pwd},
+			{Key: "fingerprint", Value: string(profile.Fingerprint.AppendTo(nil))},
+			{Key: "setup", Value: string(profile.Setup)},
+			{Key: "rtcp-mux"},
+			{Key: "rtcp-rsize"},
+		}
+
+		for _, candidate := range profile.Candidates {
+			attrs = append(attrs, sdp.Attribute{Key: "candidate", Value: string(candidate.AppendTo(nil))})
+		}
+
+		attrs = append(attrs, sdp.Attribute{Key: "end-of-candidates"})
+
+		if media.Type == sdp.Video {
+			attrs = append(attrs, feedback(media, profile.Feedback)...)
+		}
+
+		m.Attributes = append(attrs, m.Attributes...)
+	}
+
+	if len(bundle) > 0 {
+		group := sdp.Group{Semantics: sdp.Bundle, IDs: bundle}
+		desc.Session.Attributes = append(
+			[]sdp.Attribute{{Key: "group", Value: string(group.AppendTo(nil))}},
+			desc.Session.Attributes...,
+		)
+	}
+
+	return desc, nil
+}
+
+// feedback returns the rtcp-fb attributes for each payload type of the media
+func feedback(media sdp.Media, feedback []sdp.RTCPFeedback) []sdp.Attribute {
+	var attrs []sdp.Attribute
+
+	for _, format := range media.Formats {
+		pt, err := strconv.ParseUint(format, 10, 8)
+		if err != nil {
+			continue
+		}
+
+		for _, fb := range feedback {
+			fb.PayloadType, fb.Any = uint8(pt), false
+			attrs = append(attrs, sdp.Attribute{Key: "rtcp-fb", Value: string(fb.AppendTo(nil))})
+		}
+	}
+
+	return attrs
+}
+
+// removeCodecs removes the formats with the encoding names from the i-th media
+func removeCodecs(desc *sdp.Description, i int, names []string) error {
+	codecs, err := desc.Media[i].Codecs()
+	if err != nil {
+		return err
+	}
+
+	for _, codec := range codecs {
+		for _, name := range names {
+			if strings.EqualFold(codec.Name, name) {
+				if err = desc.RemoveFormat(i, strconv.Itoa(int(codec.PayloadType))); err != nil {
+					return err
+				}
+
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+func connection(address string, addrType sdp.AddrType) sdp.ConnectionInfo {
+	return sdp.ConnectionInfo{
+		NetType:  sdp.IN,
+		AddrType: addrType,
+		Address:  address,
+		TTL:      -1,
+	}
+}
@@ -0,0 +1,185 @@
+package webrtc
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gokiki/sip-server/internal/sdp"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestToLegacy(t *testing.T) {
+	profile := Legacy{
+		Origin:   origin,
+		Address:  "198.51.100.1",
+		AddrType: sdp.IP4,
+		Port: func(i int) int {
+			return 20000 + i*2
+		},
+	}
+
+	t.Run("chrome offer", func(t *testing.T) {
+		offer := parseFile(t, "chrome_offer.sdp")
+		legacy, err := ToLegacy(offer, profile)
+		require.NoError(t, err)
+		golden(t, "chrome_offer.legacy.sdp", legacy.Marshal())
+
+		// the original description stays intact
+		require.Equal(t, parseFile(t, "chrome_offer.sdp"), offer)
+
+		_, err = sdp.NewStrictParser().Parse(legacy.Marshal())
+		require.NoError(t, err)
+
+		for i, media := range legacy.Media[:2] {
+			require.Equal(t, sdp.RTPAVP, media.Proto)
+			require.Equal(t, 20000+i*2, media.Port)
+			candidates, err := media.Candidates()
+			require.NoError(t, err)
+			require.Empty(t, candidates)
+			_, found := media.MID()
+			require.False(t, found)
+		}
+
+		require.Equal(t, []string{"96", "102"}, legacy.Media[1].Formats)
+		require.Zero(t, legacy.Media[2].Port)
+		require.Empty(t, legacy.Session.Groups())
+	})
+
+	t.Run("SDES", func(t *testing.T) {
+		profile := profile
+		profile.SRTP = true
+
+		legacy, err := ToLegacy(parseFile(t, "chrome_offer.sdp"), profile)
+		require.NoError(t, err)
+		require.Equal(t, sdp.RTPSAVP, legacy.Media[0].Proto)
+
+		cryptos, err := legacy.Media[0].Cryptos()
+		require.NoError(t, err)
+		require.Len(t, cryptos, 1)
+		require.Equal(t, sdp.AESCM128HMACSHA180, cryptos[0].Suite)
+
+		cryptos, err = legacy.Media[2].Cryptos()
+		require.NoError(t, err)
+		require.Empty(t, cryptos)
+	})
+
+	t.Run("feedback-only codecs", func(t *testing.T) {
+		// only the rtx formats are left
+		offer := parseFile(t, "chrome_offer.sdp")
+		require.NoError(t, offer.RemoveFormat(1, "96"))
+		require.NoError(t, offer.RemoveFormat(1, "102"))
+
+		legacy, err := ToLegacy(offer, profile)
+		require.NoError(t, err)
+		require.Zero(t, legacy.Media[1].Port)
+		require.NotEmpty(t, legacy.Media[1].Formats)
+		require.NotZero(t, legacy.Media[0].Port)
+	})
+}
+
+func TestToWebRTC(t *testing.T) {
+	fingerprint, err := sdp.Fingerprint{}.Parse("sha-256 " +
+		"AA:BB:CC:DD:EE:FF:00:11:22:33:44:55:66:77:88:99:AA:BB:CC:DD:EE:FF:00:11:22:33:44:55:66:77:88:99")
+	require.NoError(t, err)
+
+	profile := WebRTC{
+		Origin:   origin,
+		Address:  "198.51.100.1",
+		AddrType: sdp.IP4,
+		Port:     40000,
+		ICE:      sdp.ICECredentials{Ufrag: "sRv1", Pwd: "aK3p9Lw0QzX7mN2bV5cT8yUe"},
+		Candidates: []sdp.Candidate{{
+			Foundation: "1",
+			Component:  1,
+			Transport:  "udp",
+			Priority:   2130706431,
+			Address:    "198.51.100.1",
+			Port:       40000,
+			Type:       sdp.Host,
+		}},
+		Fingerprint: fingerprint,
+		Setup:       sdp.ActPass,
+		Feedback: []sdp.RTCPFeedback{
+			{Type: "nack"},
+			{Type: "nack", Param: "pli"},
+			{Type: "ccm", Param: "fir"},
+		},
+	}
+
+	offer := parseFile(t, "sip_offer.sdp")
+	translated, err := ToWebRTC(offer, profile)
+	require.NoError(t, err)
+	golden(t, "sip_offer.webrtc.sdp", translated.Marshal())
+	require.Equal(t, parseFile(t, "sip_offer.sdp"), offer)
+
+	_, err = sdp.NewStrictParser().Parse(translated.Marshal())
+	require.NoError(t, err)
+
+	group, found := translated.BundleGroup(0)
+	require.True(t, found)
+	require.Equal(t, []string{"0", "1"}, group.IDs)
+
+	transport, err := translated.Transport(1)
+	require.NoError(t, err)
+	require.Equal(t, 40000, transport.Port)
+	require.True(t, transport.RTCPMux)
+
+	require.Zero(t, translated.Media[2].Port)
+	mid, found := translated.Media[2].MID()
+	require.True(t, found)
+	require.Equal(t, "2", mid)
+
+	t.Run("round trip", func(t *testing.T) {
+		legacy, err := ToLegacy(translated, Legacy{
+			Origin:   sdp.NewOrigin("b2bua", sdp.IP4, "198.51.100.20"),
+			Address:  "198.51.100.20",
+			AddrType: sdp.IP4,
+			Port: func(i int) int {
+				return offer.Media[i].Port
+			},
+		})
+		require.NoError(t, err)
+
+		for i, media := range legacy.Media {
+			require.Equal(t, offer.Media[i].Port, media.Port)
+			require.Equal(t, offer.Media[i].Formats, media.Formats)
+		}
+	})
+}
+
+// origin is the o= line of the bridge. The peers' ones mustn't leak through
+var origin = sdp.Origin{
+	Username:       "b2bua",
+	SessId:         1,
+	SessVersion:    1,
+	NetType:        sdp.IN,
+	AddrType:       sdp.IP4,
+	UnicastAddress: "198.51.100.1",
+}
+
+func parseFile(t *testing.T, name string) sdp.Description {
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+
+	desc, err := sdp.NewParser().Parse(data)
+	require.NoError(t, err)
+
+	return desc
+}
+
+// golden compares the data with the golden file, or overwrites the file with the
+// -update flag
+func golden(t *testing.T, name string, data []byte) {
+	path := filepath.Join("testdata", name)
+	if *update {
+		require.NoError(t, os.WriteFile(path, data, 0o644))
+	}
+
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(data))
+}
@@ -0,0 +1,53 @@
+package settings
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrUnknownKey      = errors.New("unknown key")
+	ErrBadSize         = errors.New("bad size")
+	ErrBadNumber       = errors.New("bad number")
+	ErrZeroLimit       = errors.New("limit must be positive")
+	ErrNegative        = errors.New("value must not be negative")
+	ErrPreAllocTooLong = errors.New("pre-allocated buffer exceeds the maximal length")
+)
+
+// FieldError is the problem with the particular setting. Field is the dotted YAML
+// path, e.g. headers.max_number
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (f FieldError) Error() string {
+	return f.Field + ": " + f.Err.Error()
+}
+
+func (f FieldError) Unwrap() error {
+	return f.Err
+}
+
+// Errors are all the problems found at once, so they can be fixed in a single go
+type Errors []error
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Is reports whether any of the errors matches the target
+func (e Errors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,223 @@
+package settings
+
+import (
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix starts the names of environment variables overriding the settings. The
+// rest of the name is the upper-cased dotted path, e.g. SIP_HEADERS_MAX_NUMBER
+const EnvPrefix = "SIP_"
+
+// field binds the setting to its dotted path in the configuration file
+type field struct {
+	path  string
+	value *int
+	// limit is the setting bounding the buffer pre-allocation. Fields without it are
+	// limits themselves, and must be positive
+	limit *int
+	// size tells the value is in bytes, so it may carry a unit. Counts may not
+	size bool
+}
+
+func (s *Settings) fields() []field {
+	return []field{
+		{"request_line.max_length", &s.RequestLine.MaxLength, nil, true},
+		{"request_line.buffer_prealloc", &s.RequestLine.BufferPreAlloc, &s.RequestLine.MaxLength, true},
+		{"headers.max_number", &s.Headers.MaxNumber, nil, false},
+		{"headers.max_key_length", &s.Headers.MaxKeyLength, nil, true},
+		{"headers.max_value_length", &s.Headers.MaxValueLength, nil, true},
+		{"body.max_length", &s.Body.MaxLength, nil, true},
+		{"body.buffer_prealloc", &s.Body.BufferPreAlloc, &s.Body.MaxLength, true},
+	}
+}
+
+// Load reads the YAML configuration file. See Parse
+func Load(path string) (Settings, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Settings{}, err
+	}
+
+	return Parse(data, os.LookupEnv)
+}
+
+// Parse overlays Default() with the YAML configuration and then with environment
+// variables, which the lookup returns (usually os.LookupEnv). Sections and keys are
+// named in snake case:
+//
+//	headers:
+//	  max_number: 100
+//	  max_value_length: 64KiB
+//
+// Sizes may carry B, KB, MB, GB (powers of 1000) or KiB, MiB, GiB (powers of 1024)
+// units in any case (e.g. kB), while counts (headers.max_number) are plain numbers.
+// Unknown keys are rejected. The result is validated, and all the problems are
+// reported at once as Errors
+func Parse(data []byte, lookup func(key string) (string, bool)) (Settings, error) {
+	var file map[string]map[string]string
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return Settings{}, err
+	}
+
+	s := Default()
+	fields := s.fields()
+	var errs Errors
+
+	var unknown []string
+
+	for section, values := range file {
+		if !knownSection(fields, section) {
+			// reported even if empty, so typos in section names don't go unnoticed
+			unknown = append(unknown, section)
+			continue
+		}
+
+		for key := range values {
+			if _, found := findField(fields, section+"."+key); !found {
+				unknown = append(unknown, section+"."+key)
+			}
+		}
+	}
+
+	// the map is iterated randomly, but errors are better reported in a stable order
+	sort.Strings(unknown)
+	for _, path := range unknown {
+		errs = append(errs, FieldError{Field: path, Err: ErrUnknownKey})
+	}
+
+	for _, f := range fields {
+		section, key, _ := strings.Cut(f.path, ".")
+		if value, found := file[section][key]; found {
+			if err := f.parse(value); err != nil {
+				errs = append(errs, FieldError{Field: f.path, Err: err})
+			}
+		}
+	}
+
+	if lookup != nil {
+		for _, f := range fields {
+			name := EnvPrefix + strings.ToUpper(strings.ReplaceAll(f.path, ".", "_"))
+			if value, found := lookup(name); found {
+				if err := f.parse(value); err != nil {
+					errs = append(errs, FieldError{Field: name, Err: err})
+				}
+			}
+		}
+	}
+
+	if err := s.Validate(); err != nil {
+		errs = append(errs, err.(Errors)...)
+	}
+
+	if len(errs) > 0 {
+		return s, errs
+	}
+
+	return s, nil
+}
+
+// Validate checks the settings are consistent. Each setting is checked on its own, and
+// all the problems are reported at once as Errors, in the order of fields(). Limits
+// must be positive, and buffer pre-allocations must fit between zero and the limit
+// of their section
+func (s Settings) Validate() error {
+	var errs Errors
+
+	for _, f := range s.fields() {
+		switch {
+		case f.limit == nil:
+			if *f.value <= 0 {
+				errs = append(errs, FieldError{Field: f.path, Err: ErrZeroLimit})
+			}
+		case *f.value < 0:
+			errs = append(errs, FieldError{Field: f.path, Err: ErrNegative})
+		case *f.value > *f.limit:
+			errs = append(errs, FieldError{Field: f.path, Err: ErrPreAllocTooLong})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func knownSection(fields []field, section string) bool {
+	for _, f := range fields {
+		if strings.HasPrefix(f.path, section+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func findField(fields []field, path string) (field, bool) {
+	for _, f := range fields {
+		if f.path == path {
+			return f, true
+		}
+	}
+
+	return field{}, false
+}
+
+func (f field) parse(value string) error {
+	if f.size {
+		return parseSize(value, f.value)
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || n < 0 {
+		return ErrBadNumber
+	}
+
+	*f.value = n
+
+	return nil
+}
+
+// units are ordered so that longer suffixes are tried first
+var units = []struct {
+	suffix     string
+	multiplier int
+}{
+	{"KiB", 1 << 10},
+	{"MiB", 1 << 20},
+	{"GiB", 1 << 30},
+	{"KB", 1e3},
+	{"MB", 1e6},
+	{"GB", 1e9},
+	{"B", 1},
+}
+
+// parseSize parses the number with an optional unit into dst. Units are matched case
+// insensitively
+func parseSize(value string, dst *int) error {
+	value = strings.TrimSpace(value)
+	multiplier := 1
+
+	for _, unit := range units {
+		if n := len(value) - len(unit.suffix); n >= 0 && strings.EqualFold(value[n:], unit.suffix) {
+			value = strings.TrimSpace(value[:len(value)-len(unit.suffix)])
+			multiplier = unit.multiplier
+			break
+		}
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 || n > math.MaxInt/multiplier {
+		return ErrBadSize
+	}
+
+	*dst = n * multiplier
+
+	return nil
+}
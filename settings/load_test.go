@@ -0,0 +1,163 @@
+package settings
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	noEnv := func(string) (string, bool) {
+		return "", false
+	}
+
+	t.Run("overlay defaults", func(t *testing.T) {
+		const config = "headers:\n" +
+			"  max_number: 100\n" +
+			"  max_value_length: 64KiB\n" +
+			"body:\n" +
+			"  max_length: 10 MB\n"
+
+		s, err := Parse([]byte(config), noEnv)
+		require.NoError(t, err)
+
+		expected := Default()
+		expected.Headers.MaxNumber = 100
+		expected.Headers.MaxValueLength = 64 * 1024
+		expected.Body.MaxLength = 10_000_000
+		require.Equal(t, expected, s)
+	})
+
+	t.Run("units on counts", func(t *testing.T) {
+		_, err := Parse([]byte("headers:\n  max_number: 1KiB\n"), noEnv)
+		require.Equal(t, Errors{
+			FieldError{Field: "headers.max_number", Err: ErrBadNumber},
+		}, err)
+
+		_, err = Parse(nil, func(key string) (string, bool) {
+			return "100B", key == "SIP_HEADERS_MAX_NUMBER"
+		})
+		require.Equal(t, Errors{
+			FieldError{Field: "SIP_HEADERS_MAX_NUMBER", Err: ErrBadNumber},
+		}, err)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		s, err := Parse(nil, noEnv)
+		require.NoError(t, err)
+		require.Equal(t, Default(), s)
+	})
+
+	t.Run("environment overrides", func(t *testing.T) {
+		env := map[string]string{
+			"SIP_HEADERS_MAX_NUMBER":       "50",
+			"SIP_REQUEST_LINE_MAX_LENGTH":  "2KiB",
+			"SIP_BODY_BUFFER_PREALLOC":     "512B",
+			"SIP_UNRELATED_VARIABLE_EXTRA": "whatever",
+		}
+		lookup := func(key string) (string, bool) {
+			value, found := env[key]
+			return value, found
+		}
+
+		s, err := Parse([]byte("headers:\n  max_number: 100\n"), lookup)
+		require.NoError(t, err)
+		require.Equal(t, 50, s.Headers.MaxNumber)
+		require.Equal(t, 2048, s.RequestLine.MaxLength)
+		require.Equal(t, 512, s.Body.BufferPreAlloc)
+	})
+
+	t.Run("all problems at once", func(t *testing.T) {
+		const config = "headers:\n" +
+			"  max_number: 0\n" +
+			"  max_key_len: 100\n" +
+			"request_line:\n" +
+			"  max_length: 512\n" +
+			"  buffer_prealloc: 1KiB\n" +
+			"body:\n" +
+			"  max_length: lots\n" +
+			"tls: {}\n"
+
+		_, err := Parse([]byte(config), noEnv)
+		var errs Errors
+		require.True(t, errors.As(err, &errs))
+		require.Equal(t, Errors{
+			FieldError{Field: "headers.max_key_len", Err: ErrUnknownKey},
+			FieldError{Field: "tls", Err: ErrUnknownKey},
+			FieldError{Field: "body.max_length", Err: ErrBadSize},
+			FieldError{Field: "request_line.buffer_prealloc", Err: ErrPreAllocTooLong},
+			FieldError{Field: "headers.max_number", Err: ErrZeroLimit},
+		}, errs)
+		require.ErrorIs(t, err, ErrZeroLimit)
+	})
+
+	t.Run("empty unknown section", func(t *testing.T) {
+		for _, config := range []string{"tls:\n", "tls: {}\n"} {
+			_, err := Parse([]byte(config), noEnv)
+			require.Equal(t, Errors{FieldError{Field: "tls", Err: ErrUnknownKey}}, err, config)
+		}
+	})
+
+	t.Run("malformed YAML", func(t *testing.T) {
+		_, err := Parse([]byte("headers: [1, 2]\n"), noEnv)
+		require.Error(t, err)
+	})
+}
+
+func TestValidate(t *testing.T) {
+	s := Default()
+	s.RequestLine.MaxLength = 0
+	s.RequestLine.BufferPreAlloc = -1
+	s.Headers.MaxNumber = -5
+	s.Body.BufferPreAlloc = s.Body.MaxLength + 1
+
+	require.Equal(t, Errors{
+		FieldError{Field: "request_line.max_length", Err: ErrZeroLimit},
+		FieldError{Field: "request_line.buffer_prealloc", Err: ErrNegative},
+		FieldError{Field: "headers.max_number", Err: ErrZeroLimit},
+		FieldError{Field: "body.buffer_prealloc", Err: ErrPreAllocTooLong},
+	}, s.Validate())
+	require.NoError(t, Default().Validate())
+}
+
+func TestParseSize(t *testing.T) {
+	for _, tc := range []struct {
+		Value    string
+		Expected int
+	}{
+		{"1024", 1024},
+		{"16B", 16},
+		{"64KiB", 64 << 10},
+		{"2 MiB", 2 << 20},
+		{"1GiB", 1 << 30},
+		{"3KB", 3000},
+		{"1GB", 1_000_000_000},
+		{"64kB", 64_000},
+		{"4kib", 4 << 10},
+		{"1 mb", 1_000_000},
+	} {
+		var n int
+		require.NoError(t, parseSize(tc.Value, &n), tc.Value)
+		require.Equal(t, tc.Expected, n, tc.Value)
+	}
+
+	for _, value := range []string{"", "KiB", "-1", "1.5MiB", "10 TB", "99999999999999999999"} {
+		var n int
+		require.ErrorIs(t, parseSize(value, &n), ErrBadSize, value)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("body:\n  max_length: 1MiB\n"), 0o644))
+
+	s, err := Load(path)
+	require.NoError(t, err)
+	require.Equal(t, 1<<20, s.Body.MaxLength)
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}